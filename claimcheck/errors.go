@@ -0,0 +1,16 @@
+package claimcheck
+
+import "errors"
+
+var (
+	ErrStoreNil     = errors.New("blob store is nil")
+	ErrS3ClientNil  = errors.New("s3 client is nil")
+	ErrBucketEmpty  = errors.New("bucket is empty")
+	ErrDirEmpty     = errors.New("dir is empty")
+	ErrKeyInvalid   = errors.New("blob key is invalid")
+	ErrBlobNotFound = errors.New("blob not found")
+
+	ErrReferenceInvalid = errors.New("claim-check reference is invalid")
+
+	ErrThresholdInvalid = errors.New("threshold must be GT 0")
+)
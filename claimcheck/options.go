@@ -0,0 +1,11 @@
+package claimcheck
+
+type Option func(*Publisher)
+
+// WithThreshold sets the published message size in bytes above which payloads are stored in BlobStore,
+// the size counts base64-encoded binary payloads and message attributes, the default is DefaultThreshold.
+func WithThreshold(threshold int) Option {
+	return func(p *Publisher) {
+		p.threshold = threshold
+	}
+}
@@ -0,0 +1,101 @@
+// Package claimcheck implements claim-check pattern for payloads exceeding message broker limits,
+// i.e. 256 KiB of SNS and SQS.
+// Oversized payloads are stored in BlobStore and messages are published with Reference to them,
+// consumers load the original payloads by Resolve.
+package claimcheck
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/google/uuid"
+	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/types"
+)
+
+// DefaultThreshold leaves room for SNS and SQS system attributes under their 256 KiB limit.
+const DefaultThreshold = 240 * 1024
+
+// Publisher is outbox.Publisher wrapper which stores payloads of messages larger than the threshold in BlobStore
+// and publishes Reference to them with ContentTypeReference by the wrapped publisher.
+type Publisher struct {
+	publisher outbox.Publisher
+	store     BlobStore
+	threshold int
+}
+
+func NewPublisher(publisher outbox.Publisher, store BlobStore, opts ...Option) (outbox.Publisher, error) {
+	if publisher == nil {
+		return nil, outbox.ErrPublisherNil
+	}
+	if store == nil {
+		return nil, ErrStoreNil
+	}
+
+	p := &Publisher{
+		publisher: publisher,
+		store:     store,
+		threshold: DefaultThreshold,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.threshold <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrThresholdInvalid, p.threshold)
+	}
+
+	return p, nil
+}
+
+// Publish stores the payload in BlobStore before publishing the reference, so consumers never see a dangling one.
+// Blobs are keyed by message UUID, or ID for messages without UUID, so retried publishing overwrites the same blob.
+func (p *Publisher) Publish(ctx context.Context, message types.Message) error {
+	if publishedSize(message) > p.threshold {
+		key := blobKey(message)
+
+		if err := p.store.Put(ctx, key, message.Payload); err != nil {
+			return fmt.Errorf("store.Put: %w", err)
+		}
+
+		var err error
+		message, err = toReference(message, key)
+		if err != nil {
+			return fmt.Errorf("toReference: %w", err)
+		}
+	}
+
+	if err := p.publisher.Publish(ctx, message); err != nil {
+		return fmt.Errorf("publisher.Publish: %w", err)
+	}
+
+	return nil
+}
+
+// publishedSize estimates the message size as published to a broker with text bodies, i.e. by sns.EncodeBody:
+// binary and compressed payloads are base64-encoded, so they grow by a third,
+// and message attributes count by their names and values.
+func publishedSize(message types.Message) int {
+	size := len(message.Payload)
+	if message.ContentEncoding() != "" || !types.IsTextContentType(message.ContentType) {
+		size = base64.StdEncoding.EncodedLen(size)
+	}
+
+	size += len(message.ContentType)
+	for k, v := range message.Metadata {
+		size += len(k) + len(v)
+	}
+
+	return size
+}
+
+func blobKey(message types.Message) string {
+	if message.UUID != uuid.Nil {
+		return message.UUID.String()
+	}
+
+	return strconv.FormatInt(message.ID, 10)
+}
@@ -0,0 +1,246 @@
+package claimcheck_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/claimcheck"
+	"github.com/nikolayk812/pgx-outbox/internal/fakes"
+	"github.com/nikolayk812/pgx-outbox/internal/mocks"
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+
+func TestPublisher_Publish(t *testing.T) {
+	t.Parallel()
+
+	small := fakes.FakeMessage()
+	small.UUID = uuid.New()
+	small.Metadata = nil // attributes count towards the threshold
+
+	large := fakes.FakeMessage()
+	large.UUID = uuid.New()
+	large.Payload = []byte(`{"content":"` + string(bytes.Repeat([]byte("a"), 300)) + `"}`)
+
+	compressed := fakes.FakeMessage()
+	compressed.UUID = uuid.New()
+	compressed.Payload = bytes.Repeat([]byte{0xff}, 300)
+	compressed.ContentType = types.ContentTypeProtobuf
+	compressed.Metadata = map[string]string{types.MetadataContentEncoding: types.ContentEncodingGzip, "key": "value"}
+
+	tests := []struct {
+		name          string
+		message       types.Message
+		wantReference bool
+	}{
+		{
+			name:    "small payload is published as is",
+			message: small,
+		},
+		{
+			name:          "large payload is published as reference",
+			message:       large,
+			wantReference: true,
+		},
+		{
+			name:          "content type and encoding are restored",
+			message:       compressed,
+			wantReference: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store, err := claimcheck.NewFileStore(t.TempDir())
+			require.NoError(t, err)
+
+			var published types.Message
+
+			publisherMock := mocks.NewPublisher(t)
+			publisherMock.On("Publish", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				published = args.Get(1).(types.Message) //nolint:forcetypeassert
+			}).Return(nil)
+
+			publisher, err := claimcheck.NewPublisher(publisherMock, store, claimcheck.WithThreshold(256))
+			require.NoError(t, err)
+
+			// WHEN
+			require.NoError(t, publisher.Publish(ctx, tt.message))
+
+			// THEN
+			ref, isReference := claimcheck.ParseReference(published.Payload)
+			require.Equal(t, tt.wantReference, isReference)
+
+			if tt.wantReference {
+				assert.Equal(t, claimcheck.ContentTypeReference, published.ContentType)
+				assert.Equal(t, tt.message.UUID.String(), ref.Key)
+				assert.Equal(t, len(tt.message.Payload), ref.Size)
+				assert.Empty(t, published.ContentEncoding())
+				require.NoError(t, published.Validate())
+			}
+
+			resolved, err := claimcheck.Resolve(ctx, store, published)
+			require.NoError(t, err)
+			assert.Equal(t, tt.message, resolved)
+		})
+	}
+}
+
+func TestPublisher_Publish_EncodedSize(t *testing.T) {
+	t.Parallel()
+
+	binary := fakes.FakeMessage()
+	binary.UUID = uuid.New()
+	binary.Payload = bytes.Repeat([]byte{0xff}, 200*1024) // 267 KiB base64-encoded
+	binary.ContentType = types.ContentTypeProtobuf
+
+	text := fakes.FakeMessage()
+	text.UUID = uuid.New()
+	text.Metadata = nil
+	text.Payload = []byte(`{"content":"` + string(bytes.Repeat([]byte("a"), 200*1024)) + `"}`)
+
+	tests := []struct {
+		name          string
+		message       types.Message
+		wantReference bool
+	}{
+		{
+			name:          "binary payload exceeds threshold once base64-encoded",
+			message:       binary,
+			wantReference: true,
+		},
+		{
+			name:    "text payload of the same size is published as is",
+			message: text,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store, err := claimcheck.NewFileStore(t.TempDir())
+			require.NoError(t, err)
+
+			var published types.Message
+
+			publisherMock := mocks.NewPublisher(t)
+			publisherMock.On("Publish", ctx, mock.Anything).Run(func(args mock.Arguments) {
+				published = args.Get(1).(types.Message) //nolint:forcetypeassert
+			}).Return(nil)
+
+			publisher, err := claimcheck.NewPublisher(publisherMock, store) // DefaultThreshold
+			require.NoError(t, err)
+
+			// WHEN
+			require.NoError(t, publisher.Publish(ctx, tt.message))
+
+			// THEN
+			_, isReference := claimcheck.ParseReference(published.Payload)
+			assert.Equal(t, tt.wantReference, isReference)
+		})
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	t.Parallel()
+
+	store, err := claimcheck.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		payload string
+		wantErr error
+	}{
+		{
+			name:    "invalid reference",
+			payload: `{"content":"test"}`,
+			wantErr: claimcheck.ErrReferenceInvalid,
+		},
+		{
+			name:    "missing blob",
+			payload: `{"claim_check":{"key":"missing","size":1}}`,
+			wantErr: claimcheck.ErrBlobNotFound,
+		},
+		{
+			name:    "key escaping store directory",
+			payload: `{"claim_check":{"key":"../etc","size":1}}`,
+			wantErr: claimcheck.ErrKeyInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			message := types.Message{Payload: []byte(tt.payload), ContentType: claimcheck.ContentTypeReference}
+
+			_, err := claimcheck.Resolve(ctx, store, message)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+// TestNewPublisher is just to increase coverage.
+func TestNewPublisher(t *testing.T) {
+	t.Parallel()
+
+	store, err := claimcheck.NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		publisher outbox.Publisher
+		store     claimcheck.BlobStore
+		options   []claimcheck.Option
+		wantErr   error
+	}{
+		{
+			name:    "nil publisher",
+			store:   store,
+			wantErr: outbox.ErrPublisherNil,
+		},
+		{
+			name:      "nil store",
+			publisher: &mocks.Publisher{},
+			wantErr:   claimcheck.ErrStoreNil,
+		},
+		{
+			name:      "invalid threshold",
+			publisher: &mocks.Publisher{},
+			store:     store,
+			options:   []claimcheck.Option{claimcheck.WithThreshold(0)},
+			wantErr:   claimcheck.ErrThresholdInvalid,
+		},
+		{
+			name:      "valid",
+			publisher: &mocks.Publisher{},
+			store:     store,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			publisher, err := claimcheck.NewPublisher(tt.publisher, tt.store, tt.options...)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, publisher)
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package claimcheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/nikolayk812/pgx-outbox/types"
+)
+
+// ContentTypeReference is ContentType of messages published with Reference instead of the original payload.
+// It has JSON suffix, so the reference is published as text and validated as JSON.
+const ContentTypeReference = "application/vnd.pgx-outbox.claim-check+json"
+
+// Reference points to the original payload in BlobStore,
+// it keeps the original content type and encoding to restore them in Resolve.
+type Reference struct {
+	Key             string `json:"key"`
+	Size            int    `json:"size"`
+	ContentType     string `json:"content_type,omitempty"`
+	ContentEncoding string `json:"content_encoding,omitempty"`
+}
+
+// referencePayload is the published payload, the wrapping object tells references apart from other payloads.
+type referencePayload struct {
+	ClaimCheck *Reference `json:"claim_check"`
+}
+
+// ParseReference returns the reference from the payload, false if the payload is not a reference.
+// Consumers which do not receive types.Message, i.e. SQS subscribers of SNS topic, can parse the message body.
+func ParseReference(payload []byte) (Reference, bool) {
+	var rp referencePayload
+	if err := json.Unmarshal(payload, &rp); err != nil || rp.ClaimCheck == nil || rp.ClaimCheck.Key == "" {
+		return Reference{}, false
+	}
+
+	return *rp.ClaimCheck, true
+}
+
+// Resolve returns the message with the original payload loaded from the store,
+// messages without reference are returned as is.
+func Resolve(ctx context.Context, store BlobStore, message types.Message) (types.Message, error) {
+	if message.ContentType != ContentTypeReference {
+		return message, nil
+	}
+
+	ref, ok := ParseReference(message.Payload)
+	if !ok {
+		return message, ErrReferenceInvalid
+	}
+
+	payload, err := store.Get(ctx, ref.Key)
+	if err != nil {
+		return message, fmt.Errorf("store.Get: %w", err)
+	}
+
+	message.Payload = payload
+	message.ContentType = ref.ContentType
+
+	if ref.ContentEncoding != "" {
+		message.Metadata = maps.Clone(message.Metadata)
+		if message.Metadata == nil {
+			message.Metadata = make(map[string]string, 1)
+		}
+		message.Metadata[types.MetadataContentEncoding] = ref.ContentEncoding
+	}
+
+	return message, nil
+}
+
+// toReference returns a copy of the message with the payload replaced by the reference.
+func toReference(message types.Message, key string) (types.Message, error) {
+	ref := Reference{
+		Key:             key,
+		Size:            len(message.Payload),
+		ContentType:     message.ContentType,
+		ContentEncoding: message.ContentEncoding(),
+	}
+
+	payload, err := json.Marshal(referencePayload{ClaimCheck: &ref})
+	if err != nil {
+		return message, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	message.Payload = payload
+	message.ContentType = ContentTypeReference
+
+	if ref.ContentEncoding != "" {
+		message.Metadata = maps.Clone(message.Metadata)
+		delete(message.Metadata, types.MetadataContentEncoding)
+	}
+
+	return message, nil
+}
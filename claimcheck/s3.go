@@ -0,0 +1,81 @@
+package claimcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Store is BlobStore keeping blobs as objects in an S3 bucket.
+// S3-compatible storages, i.e. MinIO, are supported by configuring the client endpoint.
+type S3Store struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Store returns S3Store writing objects to the bucket, object keys are blob keys prefixed by prefix,
+// i.e. "outbox/" prefix stores blob "abc" as object "outbox/abc".
+func NewS3Store(client *s3.Client, bucket, prefix string) (*S3Store, error) {
+	if client == nil {
+		return nil, ErrS3ClientNil
+	}
+	if bucket == "" {
+		return nil, ErrBucketEmpty
+	}
+
+	return &S3Store{
+		client: client,
+		bucket: bucket,
+		prefix: prefix,
+	}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, data []byte) error {
+	if key == "" {
+		return ErrKeyInvalid
+	}
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+		Body:   bytes.NewReader(data),
+	})
+	if err != nil {
+		return fmt.Errorf("client.PutObject: %w", err)
+	}
+
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	if key == "" {
+		return nil, ErrKeyInvalid
+	}
+
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.prefix + key),
+	})
+	if err != nil {
+		var noSuchKey *s3Types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+		}
+		return nil, fmt.Errorf("client.GetObject: %w", err)
+	}
+	defer out.Body.Close()
+
+	data, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("io.ReadAll: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,122 @@
+package claimcheck_test
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3Types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/nikolayk812/pgx-outbox/claimcheck"
+	"github.com/nikolayk812/pgx-outbox/internal/containers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const (
+	region = "eu-central-1"
+	bucket = "claim-check"
+)
+
+type S3StoreTestSuite struct {
+	suite.Suite
+	container testcontainers.Container
+
+	store *claimcheck.S3Store
+}
+
+//nolint:paralleltest
+func TestS3StoreTestSuite(t *testing.T) {
+	suite.Run(t, new(S3StoreTestSuite))
+}
+
+func (suite *S3StoreTestSuite) SetupSuite() {
+	os.Setenv("TESTCONTAINERS_RYUK_DISABLED", "true")
+
+	// sns and sqs are required by the init script
+	container, endpoint, err := containers.Localstack(ctx, "localstack/localstack:4.7.0", "sns,sqs,s3", "")
+	suite.noError(err)
+	suite.container = container
+
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region), config.WithBaseEndpoint(endpoint),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider("test", "test", "test")))
+	suite.noError(err)
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.UsePathStyle = true // Localstack does not resolve virtual-hosted buckets
+	})
+
+	_, err = client.CreateBucket(ctx, &s3.CreateBucketInput{
+		Bucket: aws.String(bucket),
+		CreateBucketConfiguration: &s3Types.CreateBucketConfiguration{
+			LocationConstraint: s3Types.BucketLocationConstraint(region),
+		},
+	})
+	suite.noError(err)
+
+	suite.store, err = claimcheck.NewS3Store(client, bucket, "outbox/")
+	suite.noError(err)
+}
+
+func (suite *S3StoreTestSuite) TearDownSuite() {
+	if suite.container != nil {
+		if err := suite.container.Terminate(ctx); err != nil {
+			slog.Error("suite.container.Terminate", slog.Any("error", err))
+		}
+	}
+}
+
+func (suite *S3StoreTestSuite) TestS3Store_PutGet() {
+	t := suite.T()
+
+	data := []byte(`{"content":"test"}`)
+
+	// WHEN
+	require.NoError(t, suite.store.Put(ctx, "key1", data))
+
+	// THEN
+	actual, err := suite.store.Get(ctx, "key1")
+	require.NoError(t, err)
+	assert.Equal(t, data, actual)
+
+	_, err = suite.store.Get(ctx, "missing")
+	require.ErrorIs(t, err, claimcheck.ErrBlobNotFound)
+}
+
+// TestNewS3Store is just to increase coverage.
+func (suite *S3StoreTestSuite) TestNewS3Store() {
+	tests := []struct {
+		name    string
+		client  *s3.Client
+		bucket  string
+		wantErr error
+	}{
+		{
+			name:    "nil client",
+			bucket:  bucket,
+			wantErr: claimcheck.ErrS3ClientNil,
+		},
+		{
+			name:    "empty bucket",
+			client:  &s3.Client{},
+			wantErr: claimcheck.ErrBucketEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			_, err := claimcheck.NewS3Store(tt.client, tt.bucket, "")
+			suite.Require().ErrorIs(err, tt.wantErr)
+		})
+	}
+}
+
+func (suite *S3StoreTestSuite) noError(err error) {
+	suite.T().Helper()
+	suite.Require().NoError(err)
+}
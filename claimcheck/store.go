@@ -0,0 +1,93 @@
+package claimcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BlobStore stores payloads of oversized messages.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type BlobStore interface {
+	// Put stores the data by the key, overwriting existing data.
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Get returns the data stored by the key, or an error matching ErrBlobNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// FileStore is BlobStore keeping blobs as files in a local directory,
+// it is meant for tests and for publishers and consumers sharing a filesystem.
+type FileStore struct {
+	dir string
+}
+
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, ErrDirEmpty
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	return &FileStore{dir: dir}, nil
+}
+
+func (s *FileStore) Put(_ context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return fmt.Errorf("path: %w", err)
+	}
+
+	// write to a temporary file first, so concurrent Get never reads a partial blob
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("tmp.Write: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("tmp.Close: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileStore) Get(_ context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, fmt.Errorf("path: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrBlobNotFound, key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	return data, nil
+}
+
+// path rejects keys escaping the store directory.
+func (s *FileStore) path(key string) (string, error) {
+	if key == "" || strings.ContainsAny(key, `/\`) || key == "." || key == ".." {
+		return "", fmt.Errorf("%w: %q", ErrKeyInvalid, key)
+	}
+
+	return filepath.Join(s.dir, key), nil
+}
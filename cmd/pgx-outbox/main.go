@@ -0,0 +1,158 @@
+// Command pgx-outbox manages replication slots and publications of wal.Reader.
+//
+// Usage:
+//
+//	pgx-outbox [-conn postgres://...] slots
+//	pgx-outbox [-conn postgres://...] drop-slot <name>
+//	pgx-outbox [-conn postgres://...] publications
+//	pgx-outbox [-conn postgres://...] drop-publication <name>
+//	pgx-outbox [-conn postgres://...] monitor -max-retained <bytes> [-interval 1m] [-slots a,b] [-drop]
+//
+// The connection string defaults to DATABASE_URL environment variable.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nikolayk812/pgx-outbox/wal"
+)
+
+var errUsage = errors.New("usage: pgx-outbox [-conn connStr] slots | drop-slot <name> | publications | " +
+	"drop-publication <name> | monitor -max-retained <bytes> [-interval 1m] [-slots a,b] [-drop]")
+
+func main() {
+	var gErr error
+
+	defer func() {
+		if gErr != nil {
+			slog.Error("global error", "error", gErr)
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}()
+
+	connStr := flag.String("conn", os.Getenv("DATABASE_URL"), "Postgres connection string")
+	flag.Parse()
+
+	if flag.NArg() == 0 || *connStr == "" {
+		gErr = errUsage
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	pool, err := pgxpool.New(ctx, *connStr)
+	if err != nil {
+		gErr = fmt.Errorf("pgxpool.New: %w", err)
+		return
+	}
+	defer pool.Close()
+
+	admin, err := wal.NewAdmin(pool)
+	if err != nil {
+		gErr = fmt.Errorf("wal.NewAdmin: %w", err)
+		return
+	}
+
+	gErr = run(ctx, admin, flag.Arg(0), flag.Args()[1:])
+}
+
+func run(ctx context.Context, admin *wal.Admin, command string, args []string) error {
+	switch command {
+	case "slots":
+		return printSlots(ctx, admin)
+	case "drop-slot":
+		if len(args) != 1 {
+			return errUsage
+		}
+		return admin.DropSlot(ctx, args[0])
+	case "publications":
+		return printPublications(ctx, admin)
+	case "drop-publication":
+		if len(args) != 1 {
+			return errUsage
+		}
+		return admin.DropPublication(ctx, args[0])
+	case "monitor":
+		return monitor(ctx, admin, args)
+	default:
+		return fmt.Errorf("unknown command[%s]: %w", command, errUsage)
+	}
+}
+
+func printSlots(ctx context.Context, admin *wal.Admin) error {
+	slots, err := admin.Slots(ctx)
+	if err != nil {
+		return fmt.Errorf("admin.Slots: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tPLUGIN\tACTIVE\tTEMPORARY\tCONFIRMED_LSN\tLAG_BYTES\tRETAINED_BYTES")
+
+	for _, slot := range slots {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%t\t%t\t%s\t%d\t%d\n", slot.Name, slot.Plugin, slot.Active, slot.Temporary,
+			slot.ConfirmedLSN, slot.LagBytes, slot.RetainedBytes)
+	}
+
+	return w.Flush()
+}
+
+func printPublications(ctx context.Context, admin *wal.Admin) error {
+	publications, err := admin.Publications(ctx)
+	if err != nil {
+		return fmt.Errorf("admin.Publications: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tTABLES")
+
+	for _, publication := range publications {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", publication.Name, strings.Join(publication.Tables, ","))
+	}
+
+	return w.Flush()
+}
+
+func monitor(ctx context.Context, admin *wal.Admin, args []string) error {
+	fs := flag.NewFlagSet("monitor", flag.ContinueOnError)
+	maxRetained := fs.Int64("max-retained", 0, "max WAL bytes retained by a slot")
+	interval := fs.Duration("interval", time.Minute, "check interval")
+	slots := fs.String("slots", "", "comma-separated slots to check, all by default")
+	drop := fs.Bool("drop", false, "drop inactive slots exceeding the threshold, requires -slots")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("fs.Parse: %w", err)
+	}
+
+	opts := []wal.MonitorOption{wal.WithMonitorInterval(*interval)}
+	if *slots != "" {
+		opts = append(opts, wal.WithMonitorSlots(strings.Split(*slots, ",")...))
+	}
+	if *drop {
+		opts = append(opts, wal.WithDropExceeded())
+	}
+
+	m, err := wal.NewMonitor(admin, *maxRetained, opts...)
+	if err != nil {
+		return fmt.Errorf("wal.NewMonitor: %w", err)
+	}
+
+	if err := m.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("monitor.Run: %w", err)
+	}
+
+	return nil
+}
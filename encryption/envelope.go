@@ -0,0 +1,147 @@
+// Package encryption implements envelope encryption of outbox message payloads:
+// each payload is encrypted by AES-256-GCM with a random data key,
+// the data key is wrapped by a key-encryption key of KeyProvider and stored in Metadata with the key ID.
+// Key-encryption keys can be rotated, as messages record the ID of the key which wrapped their data key.
+package encryption
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"maps"
+
+	"github.com/nikolayk812/pgx-outbox/types"
+)
+
+// Envelope implements outbox.Encryptor and outbox.Decryptor.
+type Envelope struct {
+	keys KeyProvider
+}
+
+func NewEnvelope(keys KeyProvider) (*Envelope, error) {
+	if keys == nil {
+		return nil, ErrKeyProviderNil
+	}
+
+	return &Envelope{keys: keys}, nil
+}
+
+// Encrypt returns a copy of the message with the payload encrypted.
+// Message UUID is authenticated as additional data, so encrypted payloads cannot be swapped between messages.
+// Messages with encryption Metadata keys already set are rejected, as their payloads are not known to be encrypted.
+func (e *Envelope) Encrypt(ctx context.Context, message types.Message) (types.Message, error) {
+	for _, key := range []string{types.MetadataEncryptionKeyID, types.MetadataEncryptionDataKey} {
+		if _, ok := message.Metadata[key]; ok {
+			return message, fmt.Errorf("%w: key[%s]", ErrMetadataPreset, key)
+		}
+	}
+
+	keyID, err := e.keys.CurrentKeyID(ctx)
+	if err != nil {
+		return message, fmt.Errorf("keys.CurrentKeyID: %w", err)
+	}
+
+	dataKey := make([]byte, keySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return message, fmt.Errorf("rand.Read: %w", err)
+	}
+
+	wrappedKey, err := e.keys.WrapKey(ctx, keyID, dataKey)
+	if err != nil {
+		return message, fmt.Errorf("keys.WrapKey: %w", err)
+	}
+
+	ciphertext, err := seal(dataKey, message.Payload, message.UUID[:])
+	if err != nil {
+		return message, fmt.Errorf("seal: %w", err)
+	}
+
+	message.Payload = ciphertext
+	message.Metadata = maps.Clone(message.Metadata)
+	if message.Metadata == nil {
+		message.Metadata = make(map[string]string, 2)
+	}
+	message.Metadata[types.MetadataEncryptionKeyID] = keyID
+	message.Metadata[types.MetadataEncryptionDataKey] = base64.StdEncoding.EncodeToString(wrappedKey)
+
+	return message, nil
+}
+
+// Decrypt returns a copy of the message with the payload decrypted and encryption metadata removed,
+// messages without encryption are returned as is.
+func (e *Envelope) Decrypt(ctx context.Context, message types.Message) (types.Message, error) {
+	if !message.IsEncrypted() {
+		return message, nil
+	}
+
+	keyID, dataKey, err := e.unwrap(ctx, message)
+	if err != nil {
+		return message, fmt.Errorf("unwrap key[%s]: %w", keyID, err)
+	}
+
+	plaintext, err := open(dataKey, message.Payload, message.UUID[:])
+	if err != nil {
+		return message, fmt.Errorf("open: %w", err)
+	}
+
+	message.Payload = plaintext
+	message.Metadata = maps.Clone(message.Metadata)
+	delete(message.Metadata, types.MetadataEncryptionKeyID)
+	delete(message.Metadata, types.MetadataEncryptionDataKey)
+	if len(message.Metadata) == 0 {
+		message.Metadata = nil
+	}
+
+	return message, nil
+}
+
+// Rewrap returns a copy of the message with the data key wrapped by the current key-encryption key,
+// the payload is not re-encrypted. It is used to retire old key-encryption keys after rotation,
+// i.e. for messages stored in the outbox table or in a dead letter queue.
+func (e *Envelope) Rewrap(ctx context.Context, message types.Message) (types.Message, error) {
+	if !message.IsEncrypted() {
+		return message, nil
+	}
+
+	currentID, err := e.keys.CurrentKeyID(ctx)
+	if err != nil {
+		return message, fmt.Errorf("keys.CurrentKeyID: %w", err)
+	}
+
+	keyID, dataKey, err := e.unwrap(ctx, message)
+	if err != nil {
+		return message, fmt.Errorf("unwrap key[%s]: %w", keyID, err)
+	}
+
+	if keyID == currentID {
+		return message, nil
+	}
+
+	wrappedKey, err := e.keys.WrapKey(ctx, currentID, dataKey)
+	if err != nil {
+		return message, fmt.Errorf("keys.WrapKey: %w", err)
+	}
+
+	message.Metadata = maps.Clone(message.Metadata)
+	message.Metadata[types.MetadataEncryptionKeyID] = currentID
+	message.Metadata[types.MetadataEncryptionDataKey] = base64.StdEncoding.EncodeToString(wrappedKey)
+
+	return message, nil
+}
+
+func (e *Envelope) unwrap(ctx context.Context, message types.Message) (string, []byte, error) {
+	keyID := message.Metadata[types.MetadataEncryptionKeyID]
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(message.Metadata[types.MetadataEncryptionDataKey])
+	if err != nil || len(wrappedKey) == 0 {
+		return keyID, nil, ErrDataKeyInvalid
+	}
+
+	dataKey, err := e.keys.UnwrapKey(ctx, keyID, wrappedKey)
+	if err != nil {
+		return keyID, nil, fmt.Errorf("keys.UnwrapKey: %w", err)
+	}
+
+	return keyID, dataKey, nil
+}
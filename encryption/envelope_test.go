@@ -0,0 +1,213 @@
+package encryption_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/nikolayk812/pgx-outbox/encryption"
+	"github.com/nikolayk812/pgx-outbox/internal/fakes"
+	"github.com/nikolayk812/pgx-outbox/internal/mocks"
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+
+func newKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func fakeMessage() types.Message {
+	message := fakes.FakeMessage()
+	message.UUID = uuid.New()
+	return message
+}
+
+func TestEnvelope_EncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	keys, err := encryption.NewStaticKeyProvider("k1", map[string][]byte{"k1": newKey(1)})
+	require.NoError(t, err)
+
+	envelope, err := encryption.NewEnvelope(keys)
+	require.NoError(t, err)
+
+	message := fakeMessage()
+
+	// WHEN
+	encrypted, err := envelope.Encrypt(ctx, message)
+	require.NoError(t, err)
+
+	// THEN
+	assert.True(t, encrypted.IsEncrypted())
+	assert.Equal(t, "k1", encrypted.Metadata[types.MetadataEncryptionKeyID])
+	assert.NotContains(t, string(encrypted.Payload), string(message.Payload))
+	assert.False(t, message.IsEncrypted(), "original message is not modified")
+	require.NoError(t, encrypted.Validate())
+
+	decrypted, err := envelope.Decrypt(ctx, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, message, decrypted)
+
+	// WHEN encrypted payload is moved to another message
+	other := fakeMessage()
+	other.Payload = encrypted.Payload
+	other.Metadata = encrypted.Metadata
+
+	// THEN decryption fails
+	_, err = envelope.Decrypt(ctx, other)
+	require.Error(t, err)
+
+	// WHEN data key is corrupted
+	corrupted := encrypted
+	corrupted.Metadata = map[string]string{
+		types.MetadataEncryptionKeyID:   "k1",
+		types.MetadataEncryptionDataKey: "not-base64",
+	}
+
+	// THEN decryption fails
+	_, err = envelope.Decrypt(ctx, corrupted)
+	require.ErrorIs(t, err, encryption.ErrDataKeyInvalid)
+
+	// WHEN plaintext message claims to be encrypted
+	preset := fakeMessage()
+	preset.Metadata = map[string]string{types.MetadataEncryptionKeyID: "k1"}
+
+	// THEN encryption fails instead of passing plaintext through
+	_, err = envelope.Encrypt(ctx, preset)
+	require.ErrorIs(t, err, encryption.ErrMetadataPreset)
+}
+
+func TestEnvelope_Rotation(t *testing.T) {
+	t.Parallel()
+
+	keys, err := encryption.NewStaticKeyProvider("k1", map[string][]byte{"k1": newKey(1)})
+	require.NoError(t, err)
+
+	envelope, err := encryption.NewEnvelope(keys)
+	require.NoError(t, err)
+
+	message := fakeMessage()
+
+	encryptedByK1, err := envelope.Encrypt(ctx, message)
+	require.NoError(t, err)
+
+	// WHEN the key is rotated
+	require.NoError(t, keys.AddKey("k2", newKey(2), true))
+
+	encryptedByK2, err := envelope.Encrypt(ctx, message)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", encryptedByK2.Metadata[types.MetadataEncryptionKeyID])
+
+	// THEN messages encrypted by both keys are decrypted
+	for _, encrypted := range []types.Message{encryptedByK1, encryptedByK2} {
+		decrypted, err := envelope.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, message, decrypted)
+	}
+
+	// WHEN the old message is rewrapped
+	rewrapped, err := envelope.Rewrap(ctx, encryptedByK1)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", rewrapped.Metadata[types.MetadataEncryptionKeyID])
+	assert.Equal(t, encryptedByK1.Payload, rewrapped.Payload)
+
+	// THEN it is decrypted without the old key
+	keysWithoutK1, err := encryption.NewStaticKeyProvider("k2", map[string][]byte{"k2": newKey(2)})
+	require.NoError(t, err)
+
+	envelopeWithoutK1, err := encryption.NewEnvelope(keysWithoutK1)
+	require.NoError(t, err)
+
+	decrypted, err := envelopeWithoutK1.Decrypt(ctx, rewrapped)
+	require.NoError(t, err)
+	assert.Equal(t, message, decrypted)
+
+	_, err = envelopeWithoutK1.Decrypt(ctx, encryptedByK1)
+	require.ErrorIs(t, err, encryption.ErrKeyNotFound)
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	t.Parallel()
+
+	keys, err := encryption.NewStaticKeyProvider("k1", map[string][]byte{"k1": newKey(1)})
+	require.NoError(t, err)
+
+	envelope, err := encryption.NewEnvelope(keys)
+	require.NoError(t, err)
+
+	message := fakeMessage()
+
+	// GIVEN the payload compressed and then encrypted as by outbox.Writer
+	compressed, err := message.Compressed(types.ContentEncodingGzip)
+	require.NoError(t, err)
+
+	encrypted, err := envelope.Encrypt(ctx, compressed)
+	require.NoError(t, err)
+
+	publisherMock := mocks.NewPublisher(t)
+	publisherMock.On("Publish", ctx, message).Return(nil)
+
+	publisher, err := encryption.NewPublisher(publisherMock, envelope)
+	require.NoError(t, err)
+
+	// WHEN
+	err = publisher.Publish(ctx, encrypted)
+
+	// THEN the wrapped publisher receives the original message
+	require.NoError(t, err)
+}
+
+// TestNewStaticKeyProvider is just to increase coverage.
+func TestNewStaticKeyProvider(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		currentID string
+		keys      map[string][]byte
+		wantErr   error
+	}{
+		{
+			name:      "short key",
+			currentID: "k1",
+			keys:      map[string][]byte{"k1": []byte("short")},
+			wantErr:   encryption.ErrKeyInvalid,
+		},
+		{
+			name:      "empty key id",
+			currentID: "",
+			keys:      map[string][]byte{"": newKey(1)},
+			wantErr:   encryption.ErrKeyIDEmpty,
+		},
+		{
+			name:      "missing current key",
+			currentID: "k2",
+			keys:      map[string][]byte{"k1": newKey(1)},
+			wantErr:   encryption.ErrKeyNotFound,
+		},
+		{
+			name:      "valid",
+			currentID: "k1",
+			keys:      map[string][]byte{"k1": newKey(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			keys, err := encryption.NewStaticKeyProvider(tt.currentID, tt.keys)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, keys)
+		})
+	}
+}
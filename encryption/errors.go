@@ -0,0 +1,14 @@
+package encryption
+
+import "errors"
+
+var (
+	ErrKeyProviderNil = errors.New("key provider is nil")
+	ErrEnvelopeNil    = errors.New("envelope is nil")
+	ErrKeyNotFound    = errors.New("key not found")
+	ErrKeyInvalid     = errors.New("key must be 32 bytes for AES-256")
+	ErrKeyIDEmpty     = errors.New("key id is empty")
+
+	ErrDataKeyInvalid = errors.New("encrypted data key is invalid")
+	ErrMetadataPreset = errors.New("message metadata already has encryption keys")
+)
@@ -0,0 +1,125 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+)
+
+// KeyProvider wraps data keys by key-encryption keys, i.e. keys of AWS KMS or HashiCorp Vault.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type KeyProvider interface {
+	// CurrentKeyID returns ID of the key-encryption key to wrap new data keys with.
+	CurrentKeyID(ctx context.Context) (string, error)
+
+	// WrapKey encrypts the data key by the key-encryption key with keyID.
+	WrapKey(ctx context.Context, keyID string, dataKey []byte) ([]byte, error)
+
+	// UnwrapKey decrypts the data key wrapped by WrapKey with the same keyID.
+	// It returns an error matching ErrKeyNotFound if the key-encryption key is unknown.
+	UnwrapKey(ctx context.Context, keyID string, wrappedKey []byte) ([]byte, error)
+}
+
+// StaticKeyProvider is KeyProvider keeping AES-256 key-encryption keys in memory.
+// Keys are rotated by AddKey with a new current key, old keys are kept to unwrap existing data keys.
+type StaticKeyProvider struct {
+	mu        sync.RWMutex
+	currentID string
+	keys      map[string][]byte
+}
+
+// NewStaticKeyProvider returns StaticKeyProvider with the keys by their IDs, new data keys are wrapped by currentID key.
+func NewStaticKeyProvider(currentID string, keys map[string][]byte) (*StaticKeyProvider, error) {
+	for id, key := range keys {
+		if err := validateKey(id, key); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, ok := keys[currentID]; !ok {
+		return nil, fmt.Errorf("%w: current[%s]", ErrKeyNotFound, currentID)
+	}
+
+	return &StaticKeyProvider{
+		currentID: currentID,
+		keys:      maps.Clone(keys),
+	}, nil
+}
+
+// AddKey adds the key, if current is true new data keys are wrapped by it.
+func (p *StaticKeyProvider) AddKey(id string, key []byte, current bool) error {
+	if err := validateKey(id, key); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.keys[id] = key
+	if current {
+		p.currentID = id
+	}
+
+	return nil
+}
+
+func (p *StaticKeyProvider) CurrentKeyID(_ context.Context) (string, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return p.currentID, nil
+}
+
+func (p *StaticKeyProvider) WrapKey(_ context.Context, keyID string, dataKey []byte) ([]byte, error) {
+	key, err := p.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	// key ID is authenticated, so a data key cannot be unwrapped under a different ID
+	wrapped, err := seal(key, dataKey, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("seal: %w", err)
+	}
+
+	return wrapped, nil
+}
+
+func (p *StaticKeyProvider) UnwrapKey(_ context.Context, keyID string, wrappedKey []byte) ([]byte, error) {
+	key, err := p.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := open(key, wrappedKey, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("open: %w", err)
+	}
+
+	return dataKey, nil
+}
+
+func (p *StaticKeyProvider) key(id string) ([]byte, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrKeyNotFound, id)
+	}
+
+	return key, nil
+}
+
+func validateKey(id string, key []byte) error {
+	if id == "" {
+		return ErrKeyIDEmpty
+	}
+
+	if len(key) != keySize {
+		return fmt.Errorf("%w: id[%s] got %d bytes", ErrKeyInvalid, id, len(key))
+	}
+
+	return nil
+}
@@ -0,0 +1,10 @@
+package encryption
+
+type Option func(*Publisher)
+
+// WithKeepCompressed passes compressed payloads through to the wrapped publisher after decryption.
+func WithKeepCompressed() Option {
+	return func(p *Publisher) {
+		p.keepCompressed = true
+	}
+}
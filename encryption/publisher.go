@@ -0,0 +1,59 @@
+package encryption
+
+import (
+	"context"
+	"fmt"
+
+	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/types"
+)
+
+// Publisher is outbox.Publisher wrapper which decrypts payloads before publishing by the wrapped publisher,
+// i.e. for messages read by wal.Reader, which cannot decrypt them.
+// Compressed payloads are decompressed after decryption unless WithKeepCompressed option is set.
+type Publisher struct {
+	publisher outbox.Publisher
+	envelope  *Envelope
+
+	keepCompressed bool
+}
+
+func NewPublisher(publisher outbox.Publisher, envelope *Envelope, opts ...Option) (outbox.Publisher, error) {
+	if publisher == nil {
+		return nil, outbox.ErrPublisherNil
+	}
+	if envelope == nil {
+		return nil, ErrEnvelopeNil
+	}
+
+	p := &Publisher{
+		publisher: publisher,
+		envelope:  envelope,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
+}
+
+func (p *Publisher) Publish(ctx context.Context, message types.Message) error {
+	message, err := p.envelope.Decrypt(ctx, message)
+	if err != nil {
+		return fmt.Errorf("envelope.Decrypt: %w", err)
+	}
+
+	if !p.keepCompressed {
+		message, err = message.Decompressed()
+		if err != nil {
+			return fmt.Errorf("message.Decompressed: %w", err)
+		}
+	}
+
+	if err := p.publisher.Publish(ctx, message); err != nil {
+		return fmt.Errorf("publisher.Publish: %w", err)
+	}
+
+	return nil
+}
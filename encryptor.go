@@ -0,0 +1,19 @@
+package outbox
+
+import (
+	"context"
+
+	"github.com/nikolayk812/pgx-outbox/types"
+)
+
+// Encryptor encrypts message payloads before writing, see encryption.Envelope.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type Encryptor interface {
+	Encrypt(ctx context.Context, message types.Message) (types.Message, error)
+}
+
+// Decryptor decrypts message payloads encrypted by Encryptor, messages without encryption are returned as is.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type Decryptor interface {
+	Decrypt(ctx context.Context, message types.Message) (types.Message, error)
+}
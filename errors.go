@@ -1,15 +1,73 @@
 package outbox
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
 
 var (
-	ErrTxNil             = errors.New("tx is nil")
-	ErrTxUnsupportedType = errors.New("tx has unsupported type")
+	ErrTxNil = errors.New("tx is nil")
+
+	ErrMessageDuplicate          = errors.New("message with the same idempotency key already exists")
+	ErrIdempotencyKeyUnsupported = errors.New("idempotency key is not supported by partitioned table")
+
+	ErrBinaryPayloadRequired = errors.New("non-JSON content type requires binary payload column")
+	ErrMetadataKeyReserved   = errors.New("metadata key is reserved")
 
 	ErrTableEmpty = errors.New("table is empty")
 
+	ErrPrefixEmpty                    = errors.New("prefix is empty")
+	ErrLogicalMessageFieldUnsupported = errors.New("field is not supported by logical messages")
+
 	ErrPoolNil = errors.New("pool is nil")
 
+	ErrPriorityWeightInvalid = errors.New("priority weight must be GT 0")
+
+	ErrWriterNil = errors.New("writer is nil")
+	ErrMapperNil = errors.New("mapper is nil")
+
 	ErrReaderNil    = errors.New("reader is nil")
 	ErrPublisherNil = errors.New("publisher is nil")
 )
+
+// DuplicateError is returned by Writer.WriteBatch and Writer.WriteBulk when some messages were not written,
+// as messages with the same IdempotencyKey already exist in the outbox table.
+// It matches ErrMessageDuplicate in errors.Is.
+type DuplicateError struct {
+	// Indexes of duplicate messages in the input.
+	Indexes []int
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s: indexes%v", ErrMessageDuplicate, e.Indexes)
+}
+
+func (e *DuplicateError) Is(target error) bool {
+	return target == ErrMessageDuplicate
+}
+
+// DecodeError is returned by Reader.Read along with the decoded messages,
+// when payloads of some messages cannot be decrypted or decompressed.
+// Such messages are omitted from the output, so a single corrupt payload does not block the others.
+type DecodeError struct {
+	// Errors by message IDs.
+	Errors map[int64]error
+}
+
+func (e *DecodeError) Error() string {
+	ids := slices.Sorted(maps.Keys(e.Errors))
+
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, fmt.Sprintf("id[%d]: %s", id, e.Errors[id]))
+	}
+
+	return "decode: " + strings.Join(parts, "; ")
+}
+
+func (e *DecodeError) Unwrap() []error {
+	return slices.Collect(maps.Values(e.Errors))
+}
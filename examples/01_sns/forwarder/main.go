@@ -8,9 +8,7 @@ import (
 	"os"
 	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
 	awsSns "github.com/aws/aws-sdk-go-v2/service/sns"
-	snsTypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
 	"github.com/jackc/pgx/v5/pgxpool"
 	outbox "github.com/nikolayk812/pgx-outbox"
 	"github.com/nikolayk812/pgx-outbox/examples/01_sns/clients/sns"
@@ -105,23 +103,15 @@ func main() {
 
 type simpleTransformer struct{}
 
-func (t simpleTransformer) Transform(_ context.Context, message types.Message) (*awsSns.PublishInput, error) {
+func (t simpleTransformer) Transform(ctx context.Context, message types.Message) (*awsSns.PublishInput, error) {
 	// 000000000000 is the AWS account ID for Localstack.
-	topicARN := fmt.Sprintf("arn:aws:sns:%s:000000000000:%s", region, message.Topic)
+	message.Topic = fmt.Sprintf("arn:aws:sns:%s:000000000000:%s", region, message.Topic)
 
-	input := &awsSns.PublishInput{
-		Message:  aws.String(string(message.Payload)),
-		TopicArn: &topicARN,
-	}
-
-	if len(message.Metadata) > 0 {
-		input.MessageAttributes = make(map[string]snsTypes.MessageAttributeValue)
-		for k, v := range message.Metadata {
-			input.MessageAttributes[k] = snsTypes.MessageAttributeValue{
-				DataType:    aws.String("String"),
-				StringValue: aws.String(v),
-			}
-		}
+	// binary payloads are base64-encoded, content type and encoding are set as message attributes,
+	// as well as uuid and created_at for consumer-side deduplication and latency measurement
+	input, err := outboxSns.DefaultTransformer{}.Transform(ctx, message)
+	if err != nil {
+		return nil, fmt.Errorf("DefaultTransformer.Transform: %w", err)
 	}
 
 	return input, nil
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
 	"github.com/nikolayk812/pgx-outbox/wal"
 )
@@ -31,7 +32,8 @@ func main() {
 
 	ctx := context.Background()
 
-	reader, err := wal.NewReader(connStr, outboxTable, publication, slot)
+	reader, err := wal.NewReader(connStr, outboxTable, publication, slot,
+		wal.WithPermanentSlot(), wal.WithReconnect(time.Second, time.Minute, 0))
 	if err != nil {
 		gErr = fmt.Errorf("wal.NewReader: %w", err)
 		return
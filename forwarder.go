@@ -2,6 +2,7 @@ package outbox
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -68,16 +69,23 @@ func NewForwarderFromPool(table string, pool *pgxpool.Pool, publisher Publisher,
 // If a message cannot be published for any reason, it would block the forwarder from making progress.
 // Hence, the forwarder progress (running in a cronjob) should be monitored and
 // an action should be taken if it stops making progress, i.e. removing a poison message from the outbox table manually.
+// Messages which cannot be decoded by the reader are not published, the others are forwarded
+// and *DecodeError is returned afterwards.
 func (f *forwarder) Forward(ctx context.Context, limit int) (types.ForwardOutput, error) {
 	var fs types.ForwardOutput
 
-	messages, err := f.reader.Read(ctx, limit)
-	if err != nil {
-		return fs, fmt.Errorf("reader.Read: %w", err)
+	// messages which cannot be decoded are reported after the others are forwarded
+	messages, readErr := f.reader.Read(ctx, limit)
+	var decodeErr *DecodeError
+	if readErr != nil && !errors.As(readErr, &decodeErr) {
+		return fs, fmt.Errorf("reader.Read: %w", readErr)
+	}
+	if decodeErr != nil {
+		readErr = fmt.Errorf("reader.Read: %w", readErr)
 	}
 
 	if len(messages) == 0 {
-		return fs, nil
+		return fs, readErr
 	}
 
 	fs.Read = messages
@@ -92,10 +100,11 @@ func (f *forwarder) Forward(ctx context.Context, limit int) (types.ForwardOutput
 	ids := types.Messages(messages).IDs()
 
 	// if it fails here, messages would be published again on the next run
+	var err error
 	fs.AckedIDs, err = f.reader.Ack(ctx, ids)
 	if err != nil {
-		return fs, fmt.Errorf("reader.Ack count[%d]: %w", len(ids), err)
+		return fs, errors.Join(readErr, fmt.Errorf("reader.Ack count[%d]: %w", len(ids), err))
 	}
 
-	return fs, nil
+	return fs, readErr
 }
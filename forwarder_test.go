@@ -120,6 +120,24 @@ func TestForwarder_Forward(t *testing.T) {
 				AckedIDs:     []int64{msg1.ID},
 			},
 		},
+		{
+			name:     "undecodable message is skipped, others are forwarded",
+			messages: types.Messages{msg1},
+			setupMocks: func(readerMock *mocks.Reader, publisherMock *mocks.Publisher) {
+				readerMock.On("Read", ctx, limit).Return([]types.Message{msg1},
+					&outbox.DecodeError{Errors: map[int64]error{msg2.ID: types.ErrDecompressedSizeExceeded}})
+
+				publisherMock.On("Publish", ctx, msg1).Return(nil)
+
+				readerMock.On("Ack", ctx, []int64{msg1.ID}).Return([]int64{msg1.ID}, nil)
+			},
+			stats: types.ForwardOutput{
+				Read:         types.Messages{msg1},
+				PublishedIDs: []int64{msg1.ID},
+				AckedIDs:     []int64{msg1.ID},
+			},
+			wantErr: true,
+		},
 		{
 			name:     "two messages, mark fails",
 			messages: types.Messages{msg1},
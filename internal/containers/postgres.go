@@ -55,7 +55,7 @@ func (c pgCustomizer) Customize(req *testcontainers.GenericContainerRequest) err
 		}
 	}
 
-	req.Cmd = append(req.Cmd, []string{"-c", "wal_level=logical"}...)
+	req.Cmd = append(req.Cmd, []string{"-c", "wal_level=logical", "-c", "max_prepared_transactions=10"}...)
 
 	return nil
 }
@@ -84,5 +84,8 @@ func (c pgCustomizer) initScripts() []string {
 		filepath.Join(dir, "01_outbox_messages.up.sql"),
 		filepath.Join(dir, "02_users.up.sql"),
 		filepath.Join(dir, "03_orders.up.sql"),
+		filepath.Join(dir, "04_outbox_messages_partitioned.up.sql"),
+		filepath.Join(dir, "05_outbox_messages_binary.up.sql"),
+		filepath.Join(dir, "06_outbox_messages_columns.up.sql"),
 	}
 }
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_outbox_messages_partitioned_published_at_null;
+DROP TABLE IF EXISTS outbox_messages_partitioned;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS outbox_messages_partitioned
+(
+    id           BIGINT GENERATED ALWAYS AS IDENTITY,
+    uuid         UUID DEFAULT gen_random_uuid()      NOT NULL,
+
+    broker       TEXT                                NOT NULL,
+    topic        TEXT                                NOT NULL,
+    metadata     JSONB,
+    payload      JSONB                               NOT NULL,
+    content_type TEXT,
+
+    deliver_at   TIMESTAMP,
+    priority     SMALLINT  DEFAULT 0                 NOT NULL,
+    -- always NULL, as a unique index of a partitioned table must include created_at, see outbox.WithPartitionedTable
+    idempotency_key TEXT,
+
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    published_at TIMESTAMP,
+
+    -- primary key of a partitioned table must include the partition key
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_partitioned_published_at_null ON outbox_messages_partitioned (published_at) WHERE published_at IS NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_outbox_messages_binary_idempotency_key;
+DROP INDEX IF EXISTS idx_outbox_messages_binary_published_at_null;
+DROP TABLE IF EXISTS outbox_messages_binary;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS outbox_messages_binary
+(
+    id           BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    uuid         UUID DEFAULT gen_random_uuid()      NOT NULL,
+
+    broker       TEXT                                NOT NULL,
+    topic        TEXT                                NOT NULL,
+    metadata     JSONB,
+    -- BYTEA instead of JSONB to store protobuf, Avro, etc., see outbox.WithBinaryPayload
+    payload      BYTEA                               NOT NULL,
+    content_type TEXT,
+
+    deliver_at   TIMESTAMP,
+    priority     SMALLINT  DEFAULT 0                 NOT NULL,
+    idempotency_key TEXT,
+
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    published_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_binary_published_at_null ON outbox_messages_binary (published_at) WHERE published_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_messages_binary_idempotency_key ON outbox_messages_binary (idempotency_key);
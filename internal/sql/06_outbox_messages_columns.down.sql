@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_outbox_messages_idempotency_key;
+
+ALTER TABLE outbox_messages
+    DROP COLUMN IF EXISTS idempotency_key,
+    DROP COLUMN IF EXISTS priority,
+    DROP COLUMN IF EXISTS deliver_at,
+    DROP COLUMN IF EXISTS content_type,
+    DROP COLUMN IF EXISTS uuid;
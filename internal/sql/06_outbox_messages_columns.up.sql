@@ -0,0 +1,9 @@
+-- upgrades outbox_messages created by 01_outbox_messages.up.sql with columns written and read by outbox.Writer and outbox.Reader
+ALTER TABLE outbox_messages
+    ADD COLUMN IF NOT EXISTS uuid            UUID DEFAULT gen_random_uuid() NOT NULL,
+    ADD COLUMN IF NOT EXISTS content_type    TEXT,
+    ADD COLUMN IF NOT EXISTS deliver_at      TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS priority        SMALLINT  DEFAULT 0            NOT NULL,
+    ADD COLUMN IF NOT EXISTS idempotency_key TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_messages_idempotency_key ON outbox_messages (idempotency_key);
@@ -0,0 +1,140 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nikolayk812/pgx-outbox/types"
+)
+
+// emitQuery emits a transactional logical decoding message and returns its LSN as a number.
+const emitQuery = "SELECT (pg_logical_emit_message(true, $1, $2::bytea) - '0/0'::pg_lsn)::bigint"
+
+// emitBulkQuery emits a transactional logical decoding message per array element in the order of the array.
+const emitBulkQuery = "SELECT (pg_logical_emit_message(true, $1, c) - '0/0'::pg_lsn)::bigint " +
+	"FROM unnest($2::bytea[]) WITH ORDINALITY AS t(c, n) ORDER BY n"
+
+type logicalMessageWriter struct {
+	prefix string
+	w      *writer
+}
+
+// NewLogicalMessageWriter creates a Writer which emits messages by pg_logical_emit_message() with the prefix
+// inside the business transaction instead of inserting them into an outbox table,
+// so there is no outbox table to clean up. Messages are read by wal.Reader with WithLogicalMessages option.
+// Returned IDs are LSNs of the emitted messages.
+// Payloads are encoded as binary, so WithBinaryPayload option is implied, WithCompression, WithEncryption
+// and WithSchemaRegistry options are supported, WithDisablePreparedBatch and WithBulkThreshold are ignored.
+// DeliverAt and IdempotencyKey message fields are not supported, as there is no table to keep them.
+func NewLogicalMessageWriter(prefix string, opts ...WriteOption) (Writer, error) {
+	if prefix == "" {
+		return nil, ErrPrefixEmpty
+	}
+
+	w := &writer{}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	w.binaryPayload = true
+
+	if err := w.validateOptions(); err != nil {
+		return nil, err
+	}
+
+	return &logicalMessageWriter{prefix: prefix, w: w}, nil
+}
+
+// Write returns an error if
+// - tx is nil
+// - message is invalid or has DeliverAt or IdempotencyKey set
+// - emit operation fails.
+func (l *logicalMessageWriter) Write(ctx context.Context, tx Querier, message types.Message) (int64, error) {
+	ids, err := l.WriteBatch(ctx, tx, []types.Message{message})
+	if err != nil {
+		return 0, err
+	}
+
+	return ids[0], nil
+}
+
+// WriteBatch emits all messages by a single statement.
+// It returns the same errors as Write.
+func (l *logicalMessageWriter) WriteBatch(ctx context.Context, tx Querier, messages []types.Message) ([]int64, error) {
+	if tx == nil {
+		return nil, ErrTxNil
+	}
+
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	contents := make([][]byte, 0, len(messages))
+
+	for idx, message := range messages {
+		content, err := l.content(ctx, message)
+		if err != nil {
+			return nil, fmt.Errorf("content idx[%d]: %w", idx, err)
+		}
+		contents = append(contents, content)
+	}
+
+	if len(contents) == 1 {
+		var id int64
+		if err := tx.QueryRow(ctx, emitQuery, l.prefix, contents[0]).Scan(&id); err != nil {
+			return nil, fmt.Errorf("tx.QueryRow: %w", err)
+		}
+		return []int64{id}, nil
+	}
+
+	ids := make([]int64, 0, len(contents))
+
+	if err := queryRows(ctx, tx, func(row pgx.Row) error {
+		var id int64
+		if err := row.Scan(&id); err != nil {
+			return fmt.Errorf("row.Scan: %w", err)
+		}
+		ids = append(ids, id)
+		return nil
+	}, emitBulkQuery, l.prefix, contents); err != nil {
+		return nil, fmt.Errorf("queryRows: %w", err)
+	}
+
+	return ids, nil
+}
+
+// WriteBulk is the same as WriteBatch.
+func (l *logicalMessageWriter) WriteBulk(ctx context.Context, tx Querier, messages []types.Message) ([]int64, error) {
+	return l.WriteBatch(ctx, tx, messages)
+}
+
+// content validates, prepares and encodes the message.
+func (l *logicalMessageWriter) content(ctx context.Context, message types.Message) ([]byte, error) {
+	if !message.DeliverAt.IsZero() {
+		return nil, fmt.Errorf("%w: DeliverAt", ErrLogicalMessageFieldUnsupported)
+	}
+	if message.IdempotencyKey != "" {
+		return nil, fmt.Errorf("%w: IdempotencyKey", ErrLogicalMessageFieldUnsupported)
+	}
+
+	if err := l.w.validate(message); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+
+	message, err := l.w.prepare(ctx, message)
+	if err != nil {
+		return nil, fmt.Errorf("prepare: %w", err)
+	}
+
+	message.CreatedAt = time.Now().UTC()
+
+	content, err := types.MarshalLogicalMessage(message)
+	if err != nil {
+		return nil, fmt.Errorf("types.MarshalLogicalMessage: %w", err)
+	}
+
+	return content, nil
+}
@@ -10,6 +10,63 @@ func WithDisablePreparedBatch() WriteOption {
 	}
 }
 
+// WithBulkThreshold sets the number of messages starting from which WriteBatch uses WriteBulk for pgx types,
+// the default is 1000, zero or negative threshold disables switching to WriteBulk.
+func WithBulkThreshold(threshold int) WriteOption {
+	return func(w *writer) {
+		w.bulkThreshold = threshold
+	}
+}
+
+// WithBinaryPayload must be set when the payload column of the outbox table is BYTEA instead of JSONB,
+// it is required to write messages with non-JSON ContentType, i.e. protobuf or Avro.
+func WithBinaryPayload() WriteOption {
+	return func(w *writer) {
+		w.binaryPayload = true
+	}
+}
+
+// WithSchemaRegistry enables validation of message payloads against schemas registered per topic,
+// messages not matching the schema are rejected with an error matching types.ErrSchemaViolation.
+// Messages of topics without registered schema are written as is.
+// See jsonschema.New for JSON Schema validator.
+func WithSchemaRegistry(registry types.SchemaRegistry) WriteOption {
+	return func(w *writer) {
+		w.schemaRegistry = registry
+	}
+}
+
+// WithCompression enables compression of payloads of at least threshold bytes by the encoding,
+// i.e. types.ContentEncodingGzip or types.ContentEncodingZstd.
+// The encoding is recorded in Metadata by types.MetadataContentEncoding key.
+// Compressed payloads are binary, hence WithBinaryPayload option is required.
+// Payloads are validated before compression.
+func WithCompression(encoding string, threshold int) WriteOption {
+	return func(w *writer) {
+		w.compression = encoding
+		w.compressionThreshold = threshold
+	}
+}
+
+// WithPartitionedTable must be set when the outbox table is partitioned,
+// messages with IdempotencyKey are rejected with ErrIdempotencyKeyUnsupported then,
+// as a unique index of a partitioned table must include created_at, so it could not deduplicate messages.
+func WithPartitionedTable() WriteOption {
+	return func(w *writer) {
+		w.partitioned = true
+	}
+}
+
+// WithEncryption enables encryption of payloads by the encryptor, i.e. encryption.Envelope,
+// so plaintext payloads are neither stored in the outbox table nor in WAL.
+// Encrypted payloads are binary, hence WithBinaryPayload option is required.
+// Payloads are validated before encryption.
+func WithEncryption(encryptor Encryptor) WriteOption {
+	return func(w *writer) {
+		w.encryptor = encryptor
+	}
+}
+
 type ReadOption func(*reader)
 
 func WithReadFilter(filter types.MessageFilter) ReadOption {
@@ -18,6 +75,33 @@ func WithReadFilter(filter types.MessageFilter) ReadOption {
 	}
 }
 
+// WithReadPriorityWeights enables reading messages with higher priority first.
+// weights maps message priority to its weight, priorities absent in the map have weight 1.
+// When several priorities have unpublished messages, a single Read returns them
+// in proportion to their weights, i.e. {10: 4, 0: 1} reads ~4 messages of priority 10 per 1 message of priority 0,
+// so low priority messages are not starved by a constant flow of high priority ones.
+func WithReadPriorityWeights(weights map[int16]int) ReadOption {
+	return func(r *reader) {
+		r.priorityWeights = weights
+	}
+}
+
+// WithReadCompressed disables decompression of payloads by Read,
+// for publishers which forward compressed payloads as is, see types.Message.ContentEncoding.
+func WithReadCompressed() ReadOption {
+	return func(r *reader) {
+		r.keepCompressed = true
+	}
+}
+
+// WithReadDecryptor enables decryption of payloads encrypted by the Encryptor of WithEncryption option,
+// so publishers receive plaintext payloads.
+func WithReadDecryptor(decryptor Decryptor) ReadOption {
+	return func(r *reader) {
+		r.decryptor = decryptor
+	}
+}
+
 type ForwardOption func(forwarder *forwarder)
 
 func WithForwardFilter(filter types.MessageFilter) ForwardOption {
@@ -0,0 +1,8 @@
+package partition
+
+import "errors"
+
+var (
+	ErrIntervalUnsupported = errors.New("partition interval is unsupported")
+	ErrCountInvalid        = errors.New("partition count must be GT 0")
+)
@@ -0,0 +1,247 @@
+package partition
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	outbox "github.com/nikolayk812/pgx-outbox"
+)
+
+const nameLayout = "20060102"
+
+// Interval is the time range covered by a single partition of the outbox table.
+type Interval int
+
+const (
+	Daily Interval = iota
+	Weekly
+)
+
+// Partition is a child table of the outbox table, covering [From, To) range of created_at values.
+type Partition struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+// Manager creates and drops partitions of an outbox table partitioned by RANGE (created_at).
+// Partitions are named as <table>_pYYYYMMDD, where the date is the start of the partition range.
+// Partitions not following this naming, i.e. DEFAULT partition, are ignored.
+// Writer, Reader and wal.Reader work with the partitioned parent table as with a regular outbox table.
+type Manager struct {
+	pool  *pgxpool.Pool
+	table string
+
+	interval        Interval
+	dropUnpublished bool
+}
+
+func NewManager(table string, pool *pgxpool.Pool, opts ...Option) (*Manager, error) {
+	if pool == nil {
+		return nil, outbox.ErrPoolNil
+	}
+	if table == "" {
+		return nil, outbox.ErrTableEmpty
+	}
+
+	m := &Manager{
+		pool:     pool,
+		table:    table,
+		interval: Daily,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.interval != Daily && m.interval != Weekly {
+		return nil, fmt.Errorf("%w: %d", ErrIntervalUnsupported, m.interval)
+	}
+
+	return m, nil
+}
+
+// CreateFuture creates count partitions starting from the partition containing from time.
+// Already existing partitions are skipped.
+// It returns names of all partitions in the range, both created and existing.
+// It is recommended to run it regularly, i.e. in a daily cronjob, creating partitions a few intervals ahead,
+// otherwise inserts into the outbox table fail when no partition matches created_at.
+func (m *Manager) CreateFuture(ctx context.Context, from time.Time, count int) ([]string, error) {
+	if count <= 0 {
+		return nil, ErrCountInvalid
+	}
+
+	names := make([]string, 0, count)
+
+	start := m.truncate(from)
+	for range count {
+		p := m.partition(start)
+
+		query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
+			p.Name, m.table, p.From.Format(time.DateTime), p.To.Format(time.DateTime))
+
+		if _, err := m.pool.Exec(ctx, query); err != nil {
+			return names, fmt.Errorf("pool.Exec[%s]: %w", p.Name, err)
+		}
+
+		names = append(names, p.Name)
+		start = p.To
+	}
+
+	return names, nil
+}
+
+// List returns partitions of the outbox table sorted by range start in ascending order.
+func (m *Manager) List(ctx context.Context) ([]Partition, error) {
+	schema, relation := m.split()
+
+	query := `SELECT c.relname FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = $1::regclass ORDER BY c.relname`
+
+	rows, err := m.pool.Query(ctx, query, m.table)
+	if err != nil {
+		return nil, fmt.Errorf("pool.Query: %w", err)
+	}
+
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("pgx.CollectRows: %w", err)
+	}
+
+	prefix := relation + "_p"
+
+	var partitions []Partition
+	for _, name := range names {
+		suffix, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+
+		start, err := time.Parse(nameLayout, suffix)
+		if err != nil {
+			continue
+		}
+
+		p := m.partition(start)
+		if schema != "" {
+			p.Name = schema + "." + name
+		}
+
+		partitions = append(partitions, p)
+	}
+
+	return partitions, nil
+}
+
+// DropExpired detaches and drops partitions whose range ends before or at the before time.
+// Partitions with unpublished messages are skipped unless WithDropUnpublished option is set.
+// It returns names of dropped partitions.
+func (m *Manager) DropExpired(ctx context.Context, before time.Time) ([]string, error) {
+	partitions, err := m.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("List: %w", err)
+	}
+
+	var dropped []string
+
+	for _, p := range partitions {
+		if p.To.After(before) {
+			break // sorted by range start
+		}
+
+		ok, err := m.drop(ctx, p.Name)
+		if err != nil {
+			return dropped, fmt.Errorf("drop[%s]: %w", p.Name, err)
+		}
+
+		if ok {
+			dropped = append(dropped, p.Name)
+		}
+	}
+
+	return dropped, nil
+}
+
+//nolint:nonamedreturns
+func (m *Manager) drop(ctx context.Context, name string) (_ bool, txErr error) {
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("pool.Begin: %w", err)
+	}
+	defer func() {
+		if txErr != nil {
+			_ = tx.Rollback(ctx)
+			return
+		}
+		if err := tx.Commit(ctx); err != nil {
+			txErr = fmt.Errorf("tx.Commit: %w", err)
+		}
+	}()
+
+	if !m.dropUnpublished {
+		var unpublished bool
+
+		query := fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE published_at IS NULL)", name)
+		if err := tx.QueryRow(ctx, query).Scan(&unpublished); err != nil {
+			return false, fmt.Errorf("tx.QueryRow: %w", err)
+		}
+
+		if unpublished {
+			return false, nil
+		}
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("ALTER TABLE %s DETACH PARTITION %s", m.table, name)); err != nil {
+		return false, fmt.Errorf("tx.Exec[detach]: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DROP TABLE %s", name)); err != nil {
+		return false, fmt.Errorf("tx.Exec[drop]: %w", err)
+	}
+
+	return true, nil
+}
+
+func (m *Manager) partition(start time.Time) Partition {
+	var end time.Time
+
+	switch m.interval {
+	case Weekly:
+		end = start.AddDate(0, 0, 7)
+	case Daily:
+		end = start.AddDate(0, 0, 1)
+	}
+
+	return Partition{
+		Name: m.table + "_p" + start.Format(nameLayout),
+		From: start,
+		To:   end,
+	}
+}
+
+// truncate returns the start of the partition containing t.
+// created_at column is TIMESTAMP without time zone, it is expected to be filled in UTC, hence t is converted to UTC.
+func (m *Manager) truncate(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	if m.interval == Weekly {
+		// weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		day = day.AddDate(0, 0, -offset)
+	}
+
+	return day
+}
+
+func (m *Manager) split() (string, string) {
+	if schema, relation, ok := strings.Cut(m.table, "."); ok {
+		return schema, relation
+	}
+
+	return "", m.table
+}
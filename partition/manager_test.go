@@ -0,0 +1,256 @@
+package partition_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/internal/containers"
+	"github.com/nikolayk812/pgx-outbox/internal/fakes"
+	"github.com/nikolayk812/pgx-outbox/partition"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const partitionedTable = "outbox_messages_partitioned"
+
+var ctx = context.Background()
+
+type ManagerTestSuite struct {
+	suite.Suite
+	pool      *pgxpool.Pool
+	container testcontainers.Container
+
+	writer outbox.Writer
+	reader outbox.Reader
+}
+
+//nolint:paralleltest
+func TestManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(ManagerTestSuite))
+}
+
+func (suite *ManagerTestSuite) SetupSuite() {
+	container, connStr, err := containers.Postgres(ctx, "postgres:17.5-alpine3.22", "")
+	suite.noError(err)
+	suite.container = container
+
+	suite.pool, err = pgxpool.New(ctx, connStr)
+	suite.noError(err)
+
+	suite.writer, err = outbox.NewWriter(partitionedTable, outbox.WithPartitionedTable())
+	suite.noError(err)
+
+	suite.reader, err = outbox.NewReader(partitionedTable, suite.pool)
+	suite.noError(err)
+}
+
+func (suite *ManagerTestSuite) TearDownSuite() {
+	if suite.pool != nil {
+		suite.pool.Close()
+	}
+	if suite.container != nil {
+		suite.noError(suite.container.Terminate(ctx))
+	}
+}
+
+func (suite *ManagerTestSuite) TestManager_CreateFuture() {
+	monday := time.Date(2025, 1, 6, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		interval partition.Interval
+		from     time.Time
+		count    int
+		want     []string
+		wantErr  error
+	}{
+		{
+			name:    "zero count",
+			from:    monday,
+			count:   0,
+			wantErr: partition.ErrCountInvalid,
+		},
+		{
+			name:     "daily",
+			interval: partition.Daily,
+			from:     monday,
+			count:    2,
+			want:     []string{partitionedTable + "_p20250106", partitionedTable + "_p20250107"},
+		},
+		{
+			name:     "daily, existing are skipped",
+			interval: partition.Daily,
+			from:     monday,
+			count:    3,
+			want: []string{
+				partitionedTable + "_p20250106", partitionedTable + "_p20250107", partitionedTable + "_p20250108",
+			},
+		},
+		{
+			name:     "weekly starts on Monday",
+			interval: partition.Weekly,
+			from:     monday.AddDate(0, 0, 14+3), // Thursday
+			count:    2,
+			want:     []string{partitionedTable + "_p20250120", partitionedTable + "_p20250127"},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			t := suite.T()
+
+			manager, err := partition.NewManager(partitionedTable, suite.pool, partition.WithInterval(tt.interval))
+			require.NoError(t, err)
+
+			names, err := manager.CreateFuture(ctx, tt.from, tt.count)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names)
+		})
+	}
+
+	suite.dropAll()
+}
+
+func (suite *ManagerTestSuite) TestManager_WriteReadDropExpired() {
+	t := suite.T()
+
+	manager, err := partition.NewManager(partitionedTable, suite.pool, partition.WithInterval(partition.Daily))
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+
+	_, err = manager.CreateFuture(ctx, now.AddDate(0, 0, -2), 4)
+	require.NoError(t, err)
+
+	// GIVEN one published message in an old partition and one unpublished in another old partition
+	published := suite.insert(now.AddDate(0, 0, -2))
+	_ = suite.insert(now.AddDate(0, 0, -1))
+
+	acked, err := suite.reader.Ack(ctx, []int64{published})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{published}, acked)
+
+	// AND a message written via Writer into the current partition
+	message := fakes.FakeMessage()
+	tx, err := suite.pool.Begin(ctx)
+	require.NoError(t, err)
+	_, err = suite.writer.Write(ctx, tx, message)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit(ctx))
+
+	messages, err := suite.reader.Read(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, messages, 2)
+
+	// WHEN
+	dropped, err := manager.DropExpired(ctx, now.AddDate(0, 0, -1).Truncate(24*time.Hour))
+	require.NoError(t, err)
+
+	// THEN only the partition with published messages is dropped
+	assert.Equal(t, []string{fmt.Sprintf("%s_p%s", partitionedTable, now.AddDate(0, 0, -2).Format("20060102"))}, dropped)
+
+	partitions, err := manager.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, partitions, 3)
+
+	// WHEN unpublished are allowed to be dropped
+	manager, err = partition.NewManager(partitionedTable, suite.pool, partition.WithDropUnpublished())
+	require.NoError(t, err)
+
+	dropped, err = manager.DropExpired(ctx, now.Truncate(24*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, dropped, 1)
+
+	suite.dropAll()
+}
+
+// TestManager_New is just to increase coverage.
+func (suite *ManagerTestSuite) TestManager_New() {
+	tests := []struct {
+		name    string
+		table   string
+		pool    *pgxpool.Pool
+		options []partition.Option
+		wantErr error
+	}{
+		{
+			name:    "empty table",
+			pool:    suite.pool,
+			wantErr: outbox.ErrTableEmpty,
+		},
+		{
+			name:    "nil pool",
+			table:   partitionedTable,
+			wantErr: outbox.ErrPoolNil,
+		},
+		{
+			name:    "unsupported interval",
+			table:   partitionedTable,
+			pool:    suite.pool,
+			options: []partition.Option{partition.WithInterval(partition.Interval(42))},
+			wantErr: partition.ErrIntervalUnsupported,
+		},
+		{
+			name:    "with all options",
+			table:   partitionedTable,
+			pool:    suite.pool,
+			options: []partition.Option{partition.WithInterval(partition.Weekly), partition.WithDropUnpublished()},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			t := suite.T()
+
+			manager, err := partition.NewManager(tt.table, tt.pool, tt.options...)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, manager)
+		})
+	}
+}
+
+// insert bypasses Writer to control created_at column.
+func (suite *ManagerTestSuite) insert(createdAt time.Time) int64 {
+	suite.T().Helper()
+
+	message := fakes.FakeMessage()
+
+	var id int64
+	err := suite.pool.QueryRow(ctx,
+		fmt.Sprintf("INSERT INTO %s (broker, topic, payload, created_at) VALUES ($1, $2, $3, $4) RETURNING id", partitionedTable),
+		message.Broker, message.Topic, string(message.Payload), createdAt).Scan(&id)
+	suite.noError(err)
+
+	return id
+}
+
+func (suite *ManagerTestSuite) dropAll() {
+	suite.T().Helper()
+
+	manager, err := partition.NewManager(partitionedTable, suite.pool, partition.WithDropUnpublished())
+	suite.noError(err)
+
+	_, err = manager.DropExpired(ctx, time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC))
+	suite.noError(err)
+}
+
+func (suite *ManagerTestSuite) noError(err error) {
+	suite.T().Helper()
+	suite.Require().NoError(err)
+}
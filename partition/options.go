@@ -0,0 +1,18 @@
+package partition
+
+type Option func(*Manager)
+
+// WithInterval sets the time range covered by a single partition, Daily by default.
+func WithInterval(interval Interval) Option {
+	return func(m *Manager) {
+		m.interval = interval
+	}
+}
+
+// WithDropUnpublished allows DropExpired to drop partitions which still contain unpublished messages.
+// By default such partitions are skipped, as dropping them would lose messages.
+func WithDropUnpublished() Option {
+	return func(m *Manager) {
+		m.dropUnpublished = true
+	}
+}
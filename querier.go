@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Querier is a transaction or a connection to write outbox messages with.
+// It is implemented by pgx.Tx, *pgx.Conn and *pgxpool.Pool,
+// database/sql types *sql.Tx, *sql.DB and *sql.Conn are adapted by SQL.
+// Outbox messages should be written in the same transaction as business entities,
+// connections and pools are supported to write messages outside a transaction.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// SQLQuerier is implemented by *sql.Tx, *sql.DB and *sql.Conn.
+type SQLQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// SQL adapts a database/sql transaction or connection to Querier, it returns nil for nil q.
+func SQL(q SQLQuerier) Querier {
+	if q == nil {
+		return nil
+	}
+
+	return sqlQuerier{q: q}
+}
+
+// batcher is implemented by pgx.Tx, *pgx.Conn and *pgxpool.Pool, but not by SQL adapter.
+type batcher interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// preparer is implemented by pgx.Tx and *pgx.Conn, but not by *pgxpool.Pool.
+type preparer interface {
+	Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error)
+}
+
+// queryRows calls fn for each row returned by the query.
+func queryRows(ctx context.Context, q Querier, fn func(row pgx.Row) error, sql string, args ...interface{}) error {
+	rows, err := q.Query(ctx, sql, args...)
+	if err != nil {
+		return fmt.Errorf("Query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return nil
+}
+
+type sqlQuerier struct {
+	q SQLQuerier
+}
+
+func (s sqlQuerier) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	rows, err := s.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("QueryContext: %w", err)
+	}
+
+	return &sqlRows{rows: rows}, nil
+}
+
+func (s sqlQuerier) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	return s.q.QueryRowContext(ctx, query, args...)
+}
+
+// sqlRows adapts *sql.Rows to pgx.Rows, pgx specific methods return zero values.
+type sqlRows struct {
+	rows *sql.Rows
+}
+
+func (r *sqlRows) Close() {
+	_ = r.rows.Close()
+}
+
+func (r *sqlRows) Err() error {
+	return r.rows.Err()
+}
+
+func (r *sqlRows) CommandTag() pgconn.CommandTag {
+	return pgconn.CommandTag{}
+}
+
+func (r *sqlRows) FieldDescriptions() []pgconn.FieldDescription {
+	return nil
+}
+
+func (r *sqlRows) Next() bool {
+	return r.rows.Next()
+}
+
+func (r *sqlRows) Scan(dest ...interface{}) error {
+	return r.rows.Scan(dest...)
+}
+
+func (r *sqlRows) Values() ([]interface{}, error) {
+	columns, err := r.rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("rows.Columns: %w", err)
+	}
+
+	values := make([]interface{}, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	if err := r.rows.Scan(dest...); err != nil {
+		return nil, fmt.Errorf("rows.Scan: %w", err)
+	}
+
+	return values, nil
+}
+
+func (r *sqlRows) RawValues() [][]byte {
+	return nil
+}
+
+func (r *sqlRows) Conn() *pgx.Conn {
+	return nil
+}
@@ -3,6 +3,8 @@ package outbox
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
@@ -32,6 +34,10 @@ type reader struct {
 	pool   *pgxpool.Pool
 	table  string
 	filter types.MessageFilter
+
+	priorityWeights map[int16]int
+	keepCompressed  bool
+	decryptor       Decryptor
 }
 
 func NewReader(table string, pool *pgxpool.Pool, opts ...ReadOption) (Reader, error) {
@@ -55,10 +61,21 @@ func NewReader(table string, pool *pgxpool.Pool, opts ...ReadOption) (Reader, er
 		opt(r)
 	}
 
+	for priority, weight := range r.priorityWeights {
+		if weight <= 0 {
+			return nil, fmt.Errorf("%w: priority[%d] weight[%d]", ErrPriorityWeightInvalid, priority, weight)
+		}
+	}
+
 	return r, nil
 }
 
 // Read returns unpublished messages sorted by ID in ascending order.
+// If WithReadPriorityWeights option is set, messages are sorted by weighted fair share of their priorities instead.
+// Messages with DeliverAt in the future are skipped until their delivery time.
+// Encrypted payloads are decrypted if WithReadDecryptor option is set, otherwise they are returned as is.
+// Compressed payloads are decompressed unless WithReadCompressed option is set.
+// Messages which cannot be decrypted or decompressed are omitted and *DecodeError is returned with the others.
 // returns an error if
 // - limit is LTE 0
 // - SQL query building or DB call fails.
@@ -67,14 +84,28 @@ func (r *reader) Read(ctx context.Context, limit int) ([]types.Message, error) {
 		return nil, fmt.Errorf("limit must be GT 0, got %d", limit)
 	}
 
+	now := time.Now().UTC()
+
+	columns := []string{
+		"id", "uuid", "broker", "topic", "metadata", "payload", "content_type", "deliver_at", "priority",
+		"created_at", "published_at",
+	}
+
 	sb := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
-		Select("id", "broker", "topic", "metadata", "payload").
+		Select(columns...).
 		From(r.table).
-		Where(sq.Eq{"published_at": nil})
+		Where(sq.Eq{"published_at": nil}).
+		Where(sq.Or{sq.Eq{"deliver_at": nil}, sq.LtOrEq{"deliver_at": now}})
 
 	sb = whereFilter(sb, r.filter)
 
-	sb = sb.OrderBy("id ASC").Limit(uint64(limit))
+	if len(r.priorityWeights) > 0 {
+		sb = orderByPriority(sb, columns, r.priorityWeights)
+	} else {
+		sb = sb.OrderBy("id ASC")
+	}
+
+	sb = sb.Limit(uint64(limit))
 
 	q, args, err := sb.ToSql()
 	if err != nil {
@@ -87,17 +118,73 @@ func (r *reader) Read(ctx context.Context, limit int) ([]types.Message, error) {
 	}
 
 	result, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (types.Message, error) {
-		var msg types.Message
-		if err := row.Scan(&msg.ID, &msg.Broker, &msg.Topic, &msg.Metadata, &msg.Payload); err != nil {
+		var (
+			msg                    types.Message
+			contentType            *string
+			deliverAt, publishedAt *time.Time
+		)
+		if err := row.Scan(&msg.ID, &msg.UUID, &msg.Broker, &msg.Topic, &msg.Metadata, &msg.Payload, &contentType,
+			&deliverAt, &msg.Priority, &msg.CreatedAt, &publishedAt); err != nil {
 			return types.Message{}, fmt.Errorf("row.Scan: %w", err)
 		}
+		if contentType != nil {
+			msg.ContentType = *contentType
+		}
+		if deliverAt != nil {
+			msg.DeliverAt = *deliverAt
+		}
+		if publishedAt != nil {
+			msg.PublishedAt = *publishedAt
+		}
 		return msg, nil
 	})
 	if err != nil {
 		return nil, fmt.Errorf("pgx.CollectRows: %w", err)
 	}
 
-	return result, nil
+	decoded := result[:0]
+	var decodeErr *DecodeError
+
+	for _, msg := range result {
+		d, err := r.decode(ctx, msg)
+		if err != nil {
+			if decodeErr == nil {
+				decodeErr = &DecodeError{Errors: map[int64]error{}}
+			}
+			decodeErr.Errors[msg.ID] = err
+			continue
+		}
+		decoded = append(decoded, d)
+	}
+
+	if decodeErr != nil {
+		return decoded, decodeErr
+	}
+
+	return decoded, nil
+}
+
+// decode decrypts and then decompresses the payload, reversing the order of Writer.
+func (r *reader) decode(ctx context.Context, msg types.Message) (types.Message, error) {
+	var err error
+
+	if r.decryptor != nil {
+		msg, err = r.decryptor.Decrypt(ctx, msg)
+		if err != nil {
+			return msg, fmt.Errorf("decryptor.Decrypt: %w", err)
+		}
+	}
+
+	if r.keepCompressed {
+		return msg, nil
+	}
+
+	msg, err = msg.Decompressed()
+	if err != nil {
+		return msg, fmt.Errorf("msg.Decompressed: %w", err)
+	}
+
+	return msg, nil
 }
 
 // Ack marks the messages by ids as published in a single transaction.
@@ -154,3 +241,34 @@ func whereFilter(sb sq.SelectBuilder, filter types.MessageFilter) sq.SelectBuild
 
 	return sb
 }
+
+// orderByPriority implements weighted fair queuing across priorities:
+// messages are ranked by ID within their priority lane, and the rank divided by the lane weight
+// is the order in which messages are read, ties are broken by higher priority first.
+func orderByPriority(sb sq.SelectBuilder, columns []string, weights map[int16]int) sq.SelectBuilder {
+	priorities := make([]int16, 0, len(weights))
+	for priority := range weights {
+		priorities = append(priorities, priority)
+	}
+	slices.Sort(priorities)
+
+	var (
+		weight strings.Builder
+		args   = make([]interface{}, 0, len(priorities)*2)
+	)
+
+	weight.WriteString("CASE priority")
+	for _, priority := range priorities {
+		weight.WriteString(" WHEN ? THEN ?")
+		args = append(args, priority, weights[priority])
+	}
+	weight.WriteString(" ELSE 1 END")
+
+	lanes := sb.Column("ROW_NUMBER() OVER (PARTITION BY priority ORDER BY id) AS lane_rank")
+
+	return sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+		Select(columns...).
+		FromSelect(lanes, "lanes").
+		OrderByClause(fmt.Sprintf("lane_rank::float8 / (%s) ASC", weight.String()), args...).
+		OrderBy("priority DESC", "id ASC")
+}
@@ -0,0 +1,51 @@
+// Package jsonschema provides types.SchemaValidator for JSON Schema, drafts 4 to 2020-12 are supported.
+package jsonschema
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// schemaURL is a placeholder location of the compiled schema, it is never fetched.
+const schemaURL = "schema.json"
+
+type validator struct {
+	schema *jsonschema.Schema
+}
+
+// New compiles the JSON Schema and returns types.SchemaValidator for it.
+// References to external schemas are not supported.
+func New(schema []byte) (types.SchemaValidator, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(schema))
+	if err != nil {
+		return nil, fmt.Errorf("jsonschema.UnmarshalJSON: %w", err)
+	}
+
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(schemaURL, doc); err != nil {
+		return nil, fmt.Errorf("compiler.AddResource: %w", err)
+	}
+
+	compiled, err := c.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("compiler.Compile: %w", err)
+	}
+
+	return &validator{schema: compiled}, nil
+}
+
+func (v *validator) ValidatePayload(payload []byte) error {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("jsonschema.UnmarshalJSON: %w", err)
+	}
+
+	if err := v.schema.Validate(doc); err != nil {
+		return fmt.Errorf("schema.Validate: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package jsonschema_test
+
+import (
+	"testing"
+
+	"github.com/nikolayk812/pgx-outbox/schema/jsonschema"
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orderSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"properties": {
+		"id": {"type": "string"},
+		"items_count": {"type": "integer", "minimum": 1}
+	},
+	"required": ["id", "items_count"]
+}`
+
+func TestValidator_ValidatePayload(t *testing.T) {
+	t.Parallel()
+
+	validator, err := jsonschema.New([]byte(orderSchema))
+	require.NoError(t, err)
+
+	registry := &types.InMemorySchemaRegistry{}
+	registry.Register("orders", validator)
+
+	tests := []struct {
+		name    string
+		message types.Message
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			message: types.Message{Topic: "orders", Payload: []byte(`{"id":"1","items_count":2}`)},
+		},
+		{
+			name:    "missing required property",
+			message: types.Message{Topic: "orders", Payload: []byte(`{"id":"1"}`)},
+			wantErr: true,
+		},
+		{
+			name:    "invalid property",
+			message: types.Message{Topic: "orders", Payload: []byte(`{"id":"1","items_count":0}`)},
+			wantErr: true,
+		},
+		{
+			name:    "not JSON",
+			message: types.Message{Topic: "orders", Payload: []byte(`invalid`)},
+			wantErr: true,
+		},
+		{
+			name:    "topic without schema",
+			message: types.Message{Topic: "users", Payload: []byte(`{}`)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tt.message.ValidateSchema(registry)
+			if tt.wantErr {
+				require.ErrorIs(t, err, types.ErrSchemaViolation)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr bool
+	}{
+		{
+			name:   "valid",
+			schema: orderSchema,
+		},
+		{
+			name:    "not JSON",
+			schema:  `invalid`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid schema",
+			schema:  `{"type": 42}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			validator, err := jsonschema.New([]byte(tt.schema))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, validator)
+		})
+	}
+}
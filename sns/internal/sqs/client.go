@@ -16,6 +16,7 @@ type Client interface {
 	GetQueueURL(ctx context.Context, queueName string) (string, error)
 	ReadOneFromSQS(ctx context.Context, queueURL string, timeout time.Duration) (types.Message, error)
 	ExtractOutboxPayload(message types.Message) ([]byte, error)
+	ExtractOutboxAttributes(message types.Message) (map[string]string, error)
 }
 
 type client struct {
@@ -84,18 +85,49 @@ func (c *client) ReadOneFromSQS(ctx context.Context, queueURL string, timeout ti
 }
 
 func (c *client) ExtractOutboxPayload(message types.Message) ([]byte, error) {
-	if message.Body == nil {
-		return nil, fmt.Errorf("message.Body is nil")
+	snsMsg, err := extractNotification(message)
+	if err != nil {
+		return nil, fmt.Errorf("extractNotification: %w", err)
+	}
+
+	return []byte(snsMsg.Message), nil
+}
+
+// ExtractOutboxAttributes returns String message attributes of SNS notification.
+func (c *client) ExtractOutboxAttributes(message types.Message) (map[string]string, error) {
+	snsMsg, err := extractNotification(message)
+	if err != nil {
+		return nil, fmt.Errorf("extractNotification: %w", err)
 	}
 
+	attributes := make(map[string]string, len(snsMsg.MessageAttributes))
+	for k, v := range snsMsg.MessageAttributes {
+		attribute, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("attribute[%s] has unexpected type: %T", k, v)
+		}
+
+		value, _ := attribute["Value"].(string)
+		attributes[k] = value
+	}
+
+	return attributes, nil
+}
+
+func extractNotification(message types.Message) (events.SNSEntity, error) {
 	var snsMsg events.SNSEntity
+
+	if message.Body == nil {
+		return snsMsg, fmt.Errorf("message.Body is nil")
+	}
+
 	if err := json.Unmarshal([]byte(*message.Body), &snsMsg); err != nil {
-		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+		return snsMsg, fmt.Errorf("json.Unmarshal: %w", err)
 	}
 
 	if snsMsg.Type != "Notification" {
-		return nil, fmt.Errorf("snsMsg.Type is not Notification: [%s]", snsMsg.Type)
+		return snsMsg, fmt.Errorf("snsMsg.Type is not Notification: [%s]", snsMsg.Type)
 	}
 
-	return []byte(snsMsg.Message), nil
+	return snsMsg, nil
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	awsSns "github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/google/uuid"
 	outbox "github.com/nikolayk812/pgx-outbox"
 	"github.com/nikolayk812/pgx-outbox/internal/containers"
 	"github.com/nikolayk812/pgx-outbox/internal/fakes"
@@ -62,7 +63,7 @@ func (suite *PublisherTestSuite) SetupSuite() {
 	suite.sqsClient, err = sqs.New(cfg)
 	suite.noError(err)
 
-	transformer := simpleTransformer{}
+	transformer := sns.DefaultTransformer{}
 
 	suite.publisher, err = sns.NewPublisher(awsSnsCli, transformer)
 	suite.noError(err)
@@ -79,6 +80,8 @@ func (suite *PublisherTestSuite) TearDownSuite() {
 func (suite *PublisherTestSuite) TestPublisher_Publish() {
 	msg1 := fakes.FakeMessage()
 	msg1.Topic = fmt.Sprintf("arn:aws:sns:%s:000000000000:%s", region, topic)
+	msg1.UUID = uuid.New()
+	msg1.CreatedAt = time.Now().UTC()
 
 	queueURL, err := suite.sqsClient.GetQueueURL(ctx, "queue1")
 	suite.noError(err)
@@ -119,6 +122,12 @@ func (suite *PublisherTestSuite) TestPublisher_Publish() {
 			require.NoError(t, err)
 
 			assert.Equal(t, msg1.Payload, outboxPayload)
+
+			attributes, err := suite.sqsClient.ExtractOutboxAttributes(sqsMessage)
+			require.NoError(t, err)
+
+			assert.Equal(t, msg1.UUID.String(), attributes[sns.AttributeUUID])
+			assert.Equal(t, msg1.CreatedAt.Format(time.RFC3339Nano), attributes[sns.AttributeCreatedAt])
 		})
 	}
 }
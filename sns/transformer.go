@@ -2,11 +2,93 @@ package sns
 
 import (
 	"context"
+	"encoding/base64"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snsTypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/google/uuid"
 	"github.com/nikolayk812/pgx-outbox/types"
 )
 
+// Message attributes describing the message body, set by EncodeBody.
+const (
+	AttributeContentType     = "content_type"
+	AttributeContentEncoding = "content_encoding"
+
+	ContentEncodingBase64 = "base64"
+)
+
+// Message attributes identifying the message, set by DefaultTransformer for consumer-side deduplication
+// and latency measurement.
+const (
+	AttributeUUID      = "uuid"
+	AttributeCreatedAt = "created_at" // RFC 3339 with nanoseconds
+)
+
 type MessageTransformer interface {
 	Transform(ctx context.Context, message types.Message) (*sns.PublishInput, error)
 }
+
+// DefaultTransformer publishes the message to Message.Topic as topic ARN.
+// The body and its attributes are set by EncodeBody, uuid and created_at attributes are set when
+// UUID and CreatedAt are non-zero, Metadata entries are forwarded as String message attributes.
+type DefaultTransformer struct{}
+
+func (DefaultTransformer) Transform(_ context.Context, message types.Message) (*sns.PublishInput, error) {
+	body, attributes := EncodeBody(message)
+
+	if message.UUID != uuid.Nil {
+		attributes[AttributeUUID] = stringAttribute(message.UUID.String())
+	}
+	if !message.CreatedAt.IsZero() {
+		attributes[AttributeCreatedAt] = stringAttribute(message.CreatedAt.UTC().Format(time.RFC3339Nano))
+	}
+
+	for k, v := range message.Metadata {
+		if _, ok := attributes[k]; ok {
+			continue // body and identity attributes take precedence
+		}
+		attributes[k] = stringAttribute(v)
+	}
+
+	return &sns.PublishInput{
+		TopicArn:          aws.String(message.Topic),
+		Message:           aws.String(body),
+		MessageAttributes: attributes,
+	}, nil
+}
+
+// EncodeBody returns SNS message body for the message payload and attributes describing it.
+// SNS message body must be text, hence binary payloads, i.e. protobuf or compressed, are base64-encoded
+// and content_encoding attribute lists applied encodings in order, i.e. "base64" or "gzip,base64".
+// content_type attribute is set for messages with non-empty ContentType.
+func EncodeBody(message types.Message) (string, map[string]snsTypes.MessageAttributeValue) {
+	attributes := make(map[string]snsTypes.MessageAttributeValue)
+
+	if message.ContentType != "" {
+		attributes[AttributeContentType] = stringAttribute(message.ContentType)
+	}
+
+	compression := message.ContentEncoding()
+
+	if compression == "" && types.IsTextContentType(message.ContentType) {
+		return string(message.Payload), attributes
+	}
+
+	encoding := ContentEncodingBase64
+	if compression != "" {
+		encoding = compression + "," + ContentEncodingBase64
+	}
+	attributes[AttributeContentEncoding] = stringAttribute(encoding)
+
+	return base64.StdEncoding.EncodeToString(message.Payload), attributes
+}
+
+func stringAttribute(value string) snsTypes.MessageAttributeValue {
+	return snsTypes.MessageAttributeValue{
+		DataType:    aws.String("String"),
+		StringValue: aws.String(value),
+	}
+}
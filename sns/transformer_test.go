@@ -0,0 +1,114 @@
+package sns_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	snsTypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/google/uuid"
+	"github.com/nikolayk812/pgx-outbox/sns"
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTransformer_Transform(t *testing.T) {
+	t.Parallel()
+
+	binary := []byte{0x0a, 0x03, 0x66, 0x6f, 0x6f}
+
+	id := uuid.MustParse("01920000-0000-7000-8000-000000000001")
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 600, time.UTC)
+
+	tests := []struct {
+		name           string
+		message        types.Message
+		wantBody       string
+		wantAttributes map[string]string
+	}{
+		{
+			name: "JSON without content type",
+			message: types.Message{
+				Topic:    "arn:aws:sns:eu-central-1:000000000000:topic",
+				Payload:  []byte(`{"id":1}`),
+				Metadata: map[string]string{"trace_id": "abc"},
+			},
+			wantBody:       `{"id":1}`,
+			wantAttributes: map[string]string{"trace_id": "abc"},
+		},
+		{
+			name: "text",
+			message: types.Message{
+				Topic:       "arn:aws:sns:eu-central-1:000000000000:topic",
+				Payload:     []byte(`hello`),
+				ContentType: "text/plain",
+			},
+			wantBody:       `hello`,
+			wantAttributes: map[string]string{sns.AttributeContentType: "text/plain"},
+		},
+		{
+			name: "protobuf is base64-encoded",
+			message: types.Message{
+				Topic:       "arn:aws:sns:eu-central-1:000000000000:topic",
+				Payload:     binary,
+				ContentType: types.ContentTypeProtobuf,
+			},
+			wantBody: base64.StdEncoding.EncodeToString(binary),
+			wantAttributes: map[string]string{
+				sns.AttributeContentType:     types.ContentTypeProtobuf,
+				sns.AttributeContentEncoding: sns.ContentEncodingBase64,
+			},
+		},
+		{
+			name: "compressed JSON is base64-encoded",
+			message: types.Message{
+				Topic:    "arn:aws:sns:eu-central-1:000000000000:topic",
+				Payload:  binary,
+				Metadata: map[string]string{types.MetadataContentEncoding: types.ContentEncodingGzip},
+			},
+			wantBody: base64.StdEncoding.EncodeToString(binary),
+			wantAttributes: map[string]string{
+				sns.AttributeContentEncoding: "gzip,base64",
+			},
+		},
+		{
+			name: "uuid and created_at",
+			message: types.Message{
+				UUID:      id,
+				CreatedAt: createdAt,
+				Topic:     "arn:aws:sns:eu-central-1:000000000000:topic",
+				Payload:   []byte(`{"id":1}`),
+				Metadata:  map[string]string{sns.AttributeUUID: "ignored"},
+			},
+			wantBody: `{"id":1}`,
+			wantAttributes: map[string]string{
+				sns.AttributeUUID:      id.String(),
+				sns.AttributeCreatedAt: "2025-01-02T03:04:05.0000006Z",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input, err := sns.DefaultTransformer{}.Transform(context.Background(), tt.message)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.message.Topic, aws.ToString(input.TopicArn))
+			assert.Equal(t, tt.wantBody, aws.ToString(input.Message))
+			assert.Equal(t, tt.wantAttributes, attributes(input.MessageAttributes))
+		})
+	}
+}
+
+func attributes(values map[string]snsTypes.MessageAttributeValue) map[string]string {
+	result := make(map[string]string, len(values))
+	for k, v := range values {
+		result[k] = aws.ToString(v.StringValue)
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nikolayk812/pgx-outbox/types"
+)
+
+// TypedWriter writes entities of type T as outbox messages, mapping them by types.ToMessageFunc.
+// Use types.JSONToMessage as the default mapper.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type TypedWriter[T any] interface {
+	// Write maps the entity to a message and writes it by Writer.Write.
+	Write(ctx context.Context, tx Querier, entity T) (int64, error)
+
+	// WriteBatch maps the entities to messages and writes them by Writer.WriteBatch.
+	WriteBatch(ctx context.Context, tx Querier, entities []T) ([]int64, error)
+}
+
+type typedWriter[T any] struct {
+	writer Writer
+	mapper types.ToMessageFunc[T]
+}
+
+func NewTypedWriter[T any](writer Writer, mapper types.ToMessageFunc[T]) (TypedWriter[T], error) {
+	if writer == nil {
+		return nil, ErrWriterNil
+	}
+	if mapper == nil {
+		return nil, ErrMapperNil
+	}
+
+	return &typedWriter[T]{
+		writer: writer,
+		mapper: mapper,
+	}, nil
+}
+
+func (w *typedWriter[T]) Write(ctx context.Context, tx Querier, entity T) (int64, error) {
+	message, err := w.mapper(entity)
+	if err != nil {
+		return 0, fmt.Errorf("mapper: %w", err)
+	}
+
+	id, err := w.writer.Write(ctx, tx, message)
+	if err != nil {
+		return id, fmt.Errorf("writer.Write: %w", err)
+	}
+
+	return id, nil
+}
+
+func (w *typedWriter[T]) WriteBatch(ctx context.Context, tx Querier, entities []T) ([]int64, error) {
+	messages := make([]types.Message, 0, len(entities))
+
+	for idx, entity := range entities {
+		message, err := w.mapper(entity)
+		if err != nil {
+			return nil, fmt.Errorf("mapper idx[%d]: %w", idx, err)
+		}
+
+		messages = append(messages, message)
+	}
+
+	ids, err := w.writer.WriteBatch(ctx, tx, messages)
+	if err != nil {
+		return ids, fmt.Errorf("writer.WriteBatch: %w", err)
+	}
+
+	return ids, nil
+}
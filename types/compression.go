@@ -0,0 +1,168 @@
+package types
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// MetadataContentEncoding is the Metadata key recording the codec of a compressed payload.
+const MetadataContentEncoding = "content_encoding"
+
+// Content encodings of compressed payloads.
+const (
+	ContentEncodingGzip = "gzip"
+	ContentEncodingZstd = "zstd"
+)
+
+// MaxDecompressedSize limits decompressed payloads, so a corrupt or malicious payload cannot exhaust memory.
+const MaxDecompressedSize = 64 << 20
+
+var (
+	ErrContentEncodingUnsupported = errors.New("content encoding is not supported")
+	ErrDecompressedSizeExceeded   = errors.New("decompressed payload size exceeds the limit")
+)
+
+var (
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+	zstdOnce    sync.Once
+)
+
+// getZstd returns encoder and decoder which are safe for concurrent use by EncodeAll and DecodeAll.
+func getZstd() (*zstd.Encoder, *zstd.Decoder, error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+		if zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil, zstd.WithDecoderMaxMemory(MaxDecompressedSize))
+	})
+	return zstdEncoder, zstdDecoder, zstdErr
+}
+
+// Compress compresses the payload with the encoding, i.e. ContentEncodingGzip.
+func Compress(payload []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case ContentEncodingGzip:
+		var buf bytes.Buffer
+
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(payload); err != nil {
+			return nil, fmt.Errorf("zw.Write: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("zw.Close: %w", err)
+		}
+
+		return buf.Bytes(), nil
+	case ContentEncodingZstd:
+		encoder, _, err := getZstd()
+		if err != nil {
+			return nil, fmt.Errorf("getZstd: %w", err)
+		}
+
+		return encoder.EncodeAll(payload, nil), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrContentEncodingUnsupported, encoding)
+	}
+}
+
+// Decompress decompresses the payload compressed by Compress with the same encoding,
+// it returns ErrDecompressedSizeExceeded for payloads decompressed to more than MaxDecompressedSize bytes.
+func Decompress(payload []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case ContentEncodingGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("gzip.NewReader: %w", err)
+		}
+		defer zr.Close()
+
+		decompressed, err := io.ReadAll(io.LimitReader(zr, MaxDecompressedSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("io.ReadAll: %w", err)
+		}
+		if len(decompressed) > MaxDecompressedSize {
+			return nil, fmt.Errorf("%w: %d", ErrDecompressedSizeExceeded, MaxDecompressedSize)
+		}
+
+		return decompressed, nil
+	case ContentEncodingZstd:
+		_, decoder, err := getZstd()
+		if err != nil {
+			return nil, fmt.Errorf("getZstd: %w", err)
+		}
+
+		decompressed, err := decoder.DecodeAll(payload, nil)
+		if errors.Is(err, zstd.ErrDecoderSizeExceeded) || errors.Is(err, zstd.ErrWindowSizeExceeded) {
+			return nil, fmt.Errorf("%w: %d", ErrDecompressedSizeExceeded, MaxDecompressedSize)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decoder.DecodeAll: %w", err)
+		}
+
+		return decompressed, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrContentEncodingUnsupported, encoding)
+	}
+}
+
+// ContentEncoding returns the codec of the compressed payload, it is empty for uncompressed payloads.
+func (m *Message) ContentEncoding() string {
+	return m.Metadata[MetadataContentEncoding]
+}
+
+// Compressed returns a copy of the message with the payload compressed by the encoding,
+// the encoding is recorded in Metadata. Metadata of the original message is not modified.
+func (m *Message) Compressed(encoding string) (Message, error) {
+	if m.ContentEncoding() != "" {
+		return *m, nil
+	}
+
+	payload, err := Compress(m.Payload, encoding)
+	if err != nil {
+		return *m, fmt.Errorf("Compress: %w", err)
+	}
+
+	c := *m
+	c.Payload = payload
+	c.Metadata = maps.Clone(m.Metadata)
+	if c.Metadata == nil {
+		c.Metadata = make(map[string]string, 1)
+	}
+	c.Metadata[MetadataContentEncoding] = encoding
+
+	return c, nil
+}
+
+// Decompressed returns a copy of the message with the payload decompressed and the encoding removed from Metadata.
+// Uncompressed messages are returned as is, encrypted messages as well, as payloads are compressed before encryption.
+func (m *Message) Decompressed() (Message, error) {
+	encoding := m.ContentEncoding()
+	if encoding == "" || m.IsEncrypted() {
+		return *m, nil
+	}
+
+	payload, err := Decompress(m.Payload, encoding)
+	if err != nil {
+		return *m, fmt.Errorf("Decompress: %w", err)
+	}
+
+	d := *m
+	d.Payload = payload
+	d.Metadata = maps.Clone(m.Metadata)
+	delete(d.Metadata, MetadataContentEncoding)
+	if len(d.Metadata) == 0 {
+		d.Metadata = nil
+	}
+
+	return d, nil
+}
@@ -0,0 +1,118 @@
+package types_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompress(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(`{"content":"` + string(bytes.Repeat([]byte("a"), 1000)) + `"}`)
+
+	tests := []struct {
+		name     string
+		encoding string
+		wantErr  error
+	}{
+		{
+			name:     "gzip",
+			encoding: types.ContentEncodingGzip,
+		},
+		{
+			name:     "zstd",
+			encoding: types.ContentEncodingZstd,
+		},
+		{
+			name:     "unsupported",
+			encoding: "brotli",
+			wantErr:  types.ErrContentEncodingUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			compressed, err := types.Compress(payload, tt.encoding)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Less(t, len(compressed), len(payload))
+
+			decompressed, err := types.Decompress(compressed, tt.encoding)
+			require.NoError(t, err)
+			assert.Equal(t, payload, decompressed)
+		})
+	}
+}
+
+func TestDecompress_SizeExceeded(t *testing.T) {
+	t.Parallel()
+
+	bomb := make([]byte, types.MaxDecompressedSize+1) // zeros compress to a tiny payload
+
+	for _, encoding := range []string{types.ContentEncodingGzip, types.ContentEncodingZstd} {
+		t.Run(encoding, func(t *testing.T) {
+			t.Parallel()
+
+			compressed, err := types.Compress(bomb, encoding)
+			require.NoError(t, err)
+
+			_, err = types.Decompress(compressed, encoding)
+			require.ErrorIs(t, err, types.ErrDecompressedSizeExceeded)
+		})
+	}
+}
+
+func TestMessage_Compressed(t *testing.T) {
+	t.Parallel()
+
+	metadata := map[string]string{"key": "value"}
+
+	message := types.Message{
+		Broker:   "sns",
+		Topic:    "topic",
+		Metadata: metadata,
+		Payload:  []byte(`{"content":"test"}`),
+	}
+
+	compressed, err := message.Compressed(types.ContentEncodingZstd)
+	require.NoError(t, err)
+
+	// THEN the original message is not modified
+	assert.Equal(t, map[string]string{"key": "value"}, metadata)
+	assert.Equal(t, types.ContentEncodingZstd, compressed.ContentEncoding())
+
+	// AND validation works on the uncompressed payload
+	require.NoError(t, compressed.Validate())
+
+	decompressed, err := compressed.Decompressed()
+	require.NoError(t, err)
+	assert.Equal(t, message, decompressed)
+
+	// WHEN the compressed payload is not JSON
+	invalid := types.Message{Broker: "sns", Topic: "topic", Payload: []byte(`invalid`)}
+	invalid, err = invalid.Compressed(types.ContentEncodingGzip)
+	require.NoError(t, err)
+
+	// THEN validation fails
+	require.Error(t, invalid.Validate())
+
+	// WHEN the payload is not compressed as recorded
+	corrupted := types.Message{
+		Broker:   "sns",
+		Topic:    "topic",
+		Metadata: map[string]string{types.MetadataContentEncoding: types.ContentEncodingGzip},
+		Payload:  []byte(`{}`),
+	}
+
+	// THEN validation fails
+	require.Error(t, corrupted.Validate())
+}
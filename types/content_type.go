@@ -0,0 +1,31 @@
+package types
+
+import "strings"
+
+// Content types of Message.Payload, other values are allowed as well.
+const (
+	ContentTypeJSON        = "application/json"
+	ContentTypeProtobuf    = "application/x-protobuf"
+	ContentTypeAvro        = "avro/binary"
+	ContentTypeOctetStream = "application/octet-stream"
+)
+
+// IsJSONContentType reports whether the content type denotes JSON, empty content type defaults to JSON.
+// Structured syntax suffix is recognized as well, i.e. application/cloudevents+json.
+func IsJSONContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	return mediaType == "" || mediaType == ContentTypeJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// IsTextContentType reports whether the content type denotes text, JSON content types included.
+func IsTextContentType(contentType string) bool {
+	if IsJSONContentType(contentType) {
+		return true
+	}
+
+	mediaType := strings.ToLower(strings.TrimSpace(contentType))
+
+	return strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+xml")
+}
@@ -0,0 +1,15 @@
+package types
+
+// Metadata keys of encrypted payloads, see encryption package.
+const (
+	// MetadataEncryptionKeyID is ID of the key-encryption key which wrapped the data key.
+	MetadataEncryptionKeyID = "encryption_key_id"
+
+	// MetadataEncryptionDataKey is base64-encoded data key wrapped by the key-encryption key.
+	MetadataEncryptionDataKey = "encryption_data_key"
+)
+
+// IsEncrypted reports whether the payload is encrypted, validation and decompression skip encrypted payloads.
+func (m *Message) IsEncrypted() bool {
+	return m.Metadata[MetadataEncryptionKeyID] != ""
+}
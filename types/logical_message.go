@@ -0,0 +1,62 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// logicalMessage is the content of a logical decoding message emitted by outbox.NewLogicalMessageWriter,
+// ID is not a part of it, as it is the LSN of the message assigned by Postgres.
+type logicalMessage struct {
+	UUID        uuid.UUID         `json:"uuid"`
+	Broker      string            `json:"broker"`
+	Topic       string            `json:"topic"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Payload     []byte            `json:"payload"`
+	ContentType string            `json:"content_type,omitempty"`
+	Priority    int16             `json:"priority,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// MarshalLogicalMessage encodes the message as the content of a logical decoding message,
+// Payload is base64-encoded, so binary payloads are supported.
+// ID, DeliverAt, IdempotencyKey and PublishedAt are not encoded.
+func MarshalLogicalMessage(m Message) ([]byte, error) {
+	data, err := json.Marshal(logicalMessage{
+		UUID:        m.UUID,
+		Broker:      m.Broker,
+		Topic:       m.Topic,
+		Metadata:    m.Metadata,
+		Payload:     m.Payload,
+		ContentType: m.ContentType,
+		Priority:    m.Priority,
+		CreatedAt:   m.CreatedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return data, nil
+}
+
+// UnmarshalLogicalMessage decodes the content of a logical decoding message encoded by MarshalLogicalMessage.
+func UnmarshalLogicalMessage(data []byte) (Message, error) {
+	var lm logicalMessage
+	if err := json.Unmarshal(data, &lm); err != nil {
+		return Message{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return Message{
+		UUID:        lm.UUID,
+		Broker:      lm.Broker,
+		Topic:       lm.Topic,
+		Metadata:    lm.Metadata,
+		Payload:     lm.Payload,
+		ContentType: lm.ContentType,
+		Priority:    lm.Priority,
+		CreatedAt:   lm.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package types_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalLogicalMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		message types.Message
+	}{
+		{
+			name: "json payload",
+			message: types.Message{
+				UUID:      uuid.New(),
+				Broker:    "sns",
+				Topic:     "topic",
+				Metadata:  map[string]string{"key": "value"},
+				Payload:   []byte(`{"name":"value"}`),
+				Priority:  5,
+				CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+			},
+		},
+		{
+			name: "binary payload",
+			message: types.Message{
+				UUID:        uuid.New(),
+				Broker:      "sns",
+				Topic:       "topic",
+				Payload:     []byte{0x00, 0xff, 0x10},
+				ContentType: types.ContentTypeProtobuf,
+				CreatedAt:   time.Now().UTC().Truncate(time.Microsecond),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			data, err := types.MarshalLogicalMessage(tt.message)
+			require.NoError(t, err)
+
+			actual, err := types.UnmarshalLogicalMessage(data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.message, actual)
+		})
+	}
+
+	_, err := types.UnmarshalLogicalMessage([]byte("not json"))
+	require.Error(t, err)
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var ErrTopicNotFound = errors.New("topic not found")
+
+// FromMessageFunc decodes a consumed message into an entity, the counterpart of ToMessageFunc.
+type FromMessageFunc[T any] func(message Message) (T, error)
+
+// TopicProvider is implemented by entities which define the topic of their messages.
+type TopicProvider interface {
+	MessageTopic() string
+}
+
+// topicTag marks a string field of an entity holding the topic of its messages, i.e. `outbox:"topic"`.
+const topicTag = "topic"
+
+// JSONToMessage returns ToMessageFunc which marshals an entity to JSON payload.
+// The topic is taken from
+// - MessageTopic method, if the entity implements TopicProvider
+// - a string field tagged as `outbox:"topic"`
+// - defaultTopic otherwise, it returns an error matching ErrTopicNotFound if defaultTopic is empty.
+func JSONToMessage[T any](broker, defaultTopic string) ToMessageFunc[T] {
+	return func(entity T) (Message, error) {
+		payload, err := json.Marshal(entity)
+		if err != nil {
+			return Message{}, fmt.Errorf("json.Marshal: %w", err)
+		}
+
+		topic := entityTopic(entity)
+		if topic == "" {
+			topic = defaultTopic
+		}
+		if topic == "" {
+			return Message{}, fmt.Errorf("%w: %T", ErrTopicNotFound, entity)
+		}
+
+		return Message{
+			Broker:  broker,
+			Topic:   topic,
+			Payload: payload,
+		}, nil
+	}
+}
+
+// JSONFromMessage returns FromMessageFunc which unmarshals JSON payload of a message into an entity.
+func JSONFromMessage[T any]() FromMessageFunc[T] {
+	return func(message Message) (T, error) {
+		var entity T
+
+		if err := json.Unmarshal(message.Payload, &entity); err != nil {
+			return entity, fmt.Errorf("json.Unmarshal: %w", err)
+		}
+
+		return entity, nil
+	}
+}
+
+func entityTopic(entity any) string {
+	if tp, ok := entity.(TopicProvider); ok {
+		return tp.MessageTopic()
+	}
+
+	v := reflect.ValueOf(entity)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Tag.Get("outbox") == topicTag && field.Type.Kind() == reflect.String {
+			return v.Field(i).String()
+		}
+	}
+
+	return ""
+}
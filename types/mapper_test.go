@@ -0,0 +1,111 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type order struct {
+	ID    string `json:"id"`
+	Topic string `json:"-" outbox:"topic"`
+}
+
+type customer struct {
+	Name string `json:"name"`
+}
+
+func (c customer) MessageTopic() string {
+	return "customers"
+}
+
+func TestJSONToMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mapFn   func() (types.Message, error)
+		want    types.Message
+		wantErr error
+	}{
+		{
+			name: "topic from method",
+			mapFn: func() (types.Message, error) {
+				return types.JSONToMessage[customer]("sns", "default")(customer{Name: "John"})
+			},
+			want: types.Message{Broker: "sns", Topic: "customers", Payload: []byte(`{"name":"John"}`)},
+		},
+		{
+			name: "topic from tag",
+			mapFn: func() (types.Message, error) {
+				return types.JSONToMessage[*order]("sns", "default")(&order{ID: "1", Topic: "orders"})
+			},
+			want: types.Message{Broker: "sns", Topic: "orders", Payload: []byte(`{"id":"1"}`)},
+		},
+		{
+			name: "default topic for empty tag",
+			mapFn: func() (types.Message, error) {
+				return types.JSONToMessage[order]("sns", "default")(order{ID: "1"})
+			},
+			want: types.Message{Broker: "sns", Topic: "default", Payload: []byte(`{"id":"1"}`)},
+		},
+		{
+			name: "default topic for map",
+			mapFn: func() (types.Message, error) {
+				return types.JSONToMessage[map[string]int]("sns", "default")(map[string]int{"a": 1})
+			},
+			want: types.Message{Broker: "sns", Topic: "default", Payload: []byte(`{"a":1}`)},
+		},
+		{
+			name: "no topic",
+			mapFn: func() (types.Message, error) {
+				return types.JSONToMessage[order]("sns", "")(order{ID: "1"})
+			},
+			wantErr: types.ErrTopicNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			message, err := tt.mapFn()
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, message)
+			require.NoError(t, message.Validate())
+		})
+	}
+}
+
+func TestJSONFromMessage(t *testing.T) {
+	t.Parallel()
+
+	decode := types.JSONFromMessage[customer]()
+
+	actual, err := decode(types.Message{Payload: []byte(`{"name":"John"}`)})
+	require.NoError(t, err)
+	assert.Equal(t, customer{Name: "John"}, actual)
+
+	_, err = decode(types.Message{Payload: []byte(`[1]`)})
+	require.Error(t, err)
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	in := customer{Name: "Jane"}
+
+	message, err := types.JSONToMessage[customer]("sns", "")(in)
+	require.NoError(t, err)
+
+	out, err := types.JSONFromMessage[customer]()(message)
+	require.NoError(t, err)
+	assert.Equal(t, in, out)
+}
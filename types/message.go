@@ -2,12 +2,19 @@ package types
 
 import (
 	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 type Message struct {
 	// ID is assigned by Postgres database after calling a Write method of outbox.Writer.
 	ID int64
 
+	// UUID is globally unique message ID, unlike ID it is meaningful for consumers, i.e. for deduplication.
+	// It is generated as UUIDv7 by outbox.Writer if not set.
+	UUID uuid.UUID
+
 	// Broker is the name of the message broker, i.e. "kafka", "sns", etc.
 	Broker string `validate:"required"`
 
@@ -18,7 +25,31 @@ type Message struct {
 	Metadata map[string]string
 
 	// Payload is the message body, ideally it should be published as is, but can be transformed in outbox.Publisher.
-	Payload []byte `validate:"required,json"`
+	// It must be valid JSON for JSON content types.
+	Payload []byte `validate:"required"`
+
+	// ContentType is optional MIME type of Payload, i.e. ContentTypeProtobuf, empty value means ContentTypeJSON.
+	// Non-JSON payloads require a BYTEA payload column, see outbox.WithBinaryPayload option.
+	ContentType string
+
+	// DeliverAt is optional time when the message becomes available for publishing, i.e. for reminders.
+	// Zero value means the message is available immediately.
+	DeliverAt time.Time
+
+	// Priority is optional, messages with higher priority are read first by outbox.Reader
+	// configured with WithReadPriorityWeights option, otherwise it is ignored.
+	Priority int16
+
+	// IdempotencyKey is optional, a message with the key already existing in the outbox table is not written again,
+	// i.e. when a retried request handler writes the same logical event in a different transaction.
+	IdempotencyKey string
+
+	// CreatedAt is the time when the message was written to the outbox table, populated by readers.
+	CreatedAt time.Time
+
+	// PublishedAt is the time when the message was marked as published in the outbox table, populated by readers.
+	// It is zero for unpublished messages.
+	PublishedAt time.Time
 }
 
 func (m *Message) Validate() error {
@@ -30,6 +61,11 @@ func (m *Message) Validate() error {
 	return v.Struct(m)
 }
 
+// IsJSON reports whether the payload is JSON according to ContentType.
+func (m *Message) IsJSON() bool {
+	return IsJSONContentType(m.ContentType)
+}
+
 type Messages []Message
 
 func (m Messages) IDs() []int64 {
@@ -49,6 +49,35 @@ func TestMessage_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid payload with JSON suffix content type",
+			message: outbox.Message{
+				Broker:      "sns",
+				Topic:       "topic",
+				Payload:     []byte(`invalid`),
+				ContentType: "application/cloudevents+json; charset=utf-8",
+			},
+			wantErr: true,
+		},
+		{
+			name: "binary payload",
+			message: outbox.Message{
+				Broker:      "sns",
+				Topic:       "topic",
+				Payload:     []byte{0x0a, 0x03, 0x66, 0x6f, 0x6f},
+				ContentType: outbox.ContentTypeProtobuf,
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty binary payload",
+			message: outbox.Message{
+				Broker:      "sns",
+				Topic:       "topic",
+				ContentType: outbox.ContentTypeProtobuf,
+			},
+			wantErr: true,
+		},
 		// Add more test cases as needed
 	}
 
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrSchemaViolation = errors.New("payload does not match schema")
+
+// SchemaValidator validates a message payload against a schema, i.e. JSON Schema, Avro or Protobuf.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type SchemaValidator interface {
+	ValidatePayload(payload []byte) error
+}
+
+// SchemaValidatorFunc is an adapter to use ordinary functions as SchemaValidator.
+type SchemaValidatorFunc func(payload []byte) error
+
+func (f SchemaValidatorFunc) ValidatePayload(payload []byte) error {
+	return f(payload)
+}
+
+// SchemaRegistry provides SchemaValidator by message topic.
+// Implementations must be safe for concurrent use by multiple goroutines.
+type SchemaRegistry interface {
+	// Validator returns false if no schema is registered for the topic.
+	Validator(topic string) (SchemaValidator, bool)
+}
+
+// InMemorySchemaRegistry is SchemaRegistry keeping validators in memory, the zero value is ready to use.
+type InMemorySchemaRegistry struct {
+	mu         sync.RWMutex
+	validators map[string]SchemaValidator
+}
+
+// Register sets the validator for the topic, replacing the previous one.
+func (r *InMemorySchemaRegistry) Register(topic string, validator SchemaValidator) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.validators == nil {
+		r.validators = make(map[string]SchemaValidator)
+	}
+
+	r.validators[topic] = validator
+}
+
+func (r *InMemorySchemaRegistry) Validator(topic string) (SchemaValidator, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	validator, ok := r.validators[topic]
+	return validator, ok
+}
+
+// ValidateSchema validates the message payload against the schema registered for its topic.
+// Messages of topics without registered schema are valid, compressed payloads are decompressed for validation,
+// encrypted payloads are not validated.
+// It returns an error matching ErrSchemaViolation if the payload does not match the schema.
+func (m *Message) ValidateSchema(registry SchemaRegistry) error {
+	if registry == nil || m.IsEncrypted() {
+		return nil
+	}
+
+	validator, ok := registry.Validator(m.Topic)
+	if !ok {
+		return nil
+	}
+
+	d, err := m.Decompressed()
+	if err != nil {
+		return fmt.Errorf("m.Decompressed: %w", err)
+	}
+
+	if err := validator.ValidatePayload(d.Payload); err != nil {
+		return fmt.Errorf("%w: topic[%s]: %w", ErrSchemaViolation, m.Topic, err)
+	}
+
+	return nil
+}
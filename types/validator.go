@@ -17,10 +17,30 @@ func getValidator() (*validator.Validate, error) {
 	once.Do(func() {
 		validate = validator.New()
 		err = validate.RegisterValidation("json", validateJSON)
+		validate.RegisterStructValidation(validateMessage, Message{})
 	})
 	return validate, err
 }
 
+// validateMessage validates the payload as JSON only for JSON content types,
+// compressed payloads are decompressed for validation, encrypted payloads are not validated.
+func validateMessage(sl validator.StructLevel) {
+	m, ok := sl.Current().Interface().(Message)
+	if !ok || len(m.Payload) == 0 || m.IsEncrypted() {
+		return
+	}
+
+	m, err := m.Decompressed()
+	if err != nil {
+		sl.ReportError(m.Payload, "Payload", "Payload", "content_encoding", m.ContentEncoding())
+		return
+	}
+
+	if m.IsJSON() && !json.Valid(m.Payload) {
+		sl.ReportError(m.Payload, "Payload", "Payload", "json", "")
+	}
+}
+
 func validateJSON(fl validator.FieldLevel) bool {
 	var js json.RawMessage
 	return json.Unmarshal(fl.Field().Bytes(), &js) == nil
@@ -0,0 +1,177 @@
+package wal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pglogrepl"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	outbox "github.com/nikolayk812/pgx-outbox"
+)
+
+// publicationComment marks publications created by Reader, so Admin drops only them.
+const publicationComment = "pgx-outbox"
+
+// Slot is a logical replication slot of the current database.
+type Slot struct {
+	Name      string
+	Plugin    string
+	Active    bool
+	Temporary bool
+
+	RestartLSN   pglogrepl.LSN // the oldest WAL position required by the slot
+	ConfirmedLSN pglogrepl.LSN // the position confirmed by the consumer
+
+	// LagBytes is WAL written since the confirmed position, not consumed yet.
+	LagBytes int64
+	// RetainedBytes is WAL retained by Postgres for the slot, it is not removed until the slot advances or is dropped.
+	RetainedBytes int64
+}
+
+// Publication is a publication created by Reader.
+type Publication struct {
+	Name   string
+	Tables []string
+}
+
+// Admin lists and drops replication slots and publications, i.e. abandoned permanent slots,
+// which make Postgres retain WAL until the disk is full. It uses a regular, not a replication connection.
+type Admin struct {
+	pool *pgxpool.Pool
+}
+
+func NewAdmin(pool *pgxpool.Pool) (*Admin, error) {
+	if pool == nil {
+		return nil, outbox.ErrPoolNil
+	}
+
+	return &Admin{pool: pool}, nil
+}
+
+// Slots returns logical replication slots of the current database sorted by name.
+func (a *Admin) Slots(ctx context.Context) ([]Slot, error) {
+	query := `SELECT slot_name, plugin, active, temporary,
+			COALESCE(restart_lsn::text, '0/0'), COALESCE(confirmed_flush_lsn::text, '0/0'),
+			COALESCE(pg_current_wal_lsn() - confirmed_flush_lsn, 0)::bigint,
+			COALESCE(pg_current_wal_lsn() - restart_lsn, 0)::bigint
+		FROM pg_replication_slots
+		WHERE slot_type = 'logical' AND database = current_database()
+		ORDER BY slot_name`
+
+	rows, err := a.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("pool.Query: %w", err)
+	}
+
+	slots, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (Slot, error) {
+		var (
+			slot                     Slot
+			restartLSN, confirmedLSN string
+		)
+
+		if err := row.Scan(&slot.Name, &slot.Plugin, &slot.Active, &slot.Temporary,
+			&restartLSN, &confirmedLSN, &slot.LagBytes, &slot.RetainedBytes); err != nil {
+			return slot, fmt.Errorf("row.Scan: %w", err)
+		}
+
+		if slot.RestartLSN, err = pglogrepl.ParseLSN(restartLSN); err != nil {
+			return slot, fmt.Errorf("pglogrepl.ParseLSN[restart]: %w", err)
+		}
+		if slot.ConfirmedLSN, err = pglogrepl.ParseLSN(confirmedLSN); err != nil {
+			return slot, fmt.Errorf("pglogrepl.ParseLSN[confirmed]: %w", err)
+		}
+
+		return slot, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pgx.CollectRows: %w", err)
+	}
+
+	return slots, nil
+}
+
+// DropSlot drops the replication slot of the current database.
+// returns an error if
+// - the slot does not exist
+// - the slot is active, i.e. Reader is running.
+func (a *Admin) DropSlot(ctx context.Context, name string) error {
+	var active bool
+
+	err := a.pool.QueryRow(ctx, `SELECT active FROM pg_replication_slots
+		WHERE slot_name = $1 AND slot_type = 'logical' AND database = current_database()`, name).Scan(&active)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", ErrReplicationSlotNotFound, name)
+		}
+		return fmt.Errorf("pool.QueryRow: %w", err)
+	}
+
+	if active {
+		return fmt.Errorf("%w: %s", ErrReplicationSlotActive, name)
+	}
+
+	if _, err := a.pool.Exec(ctx, "SELECT pg_drop_replication_slot($1)", name); err != nil {
+		return fmt.Errorf("pool.Exec: %w", err)
+	}
+
+	return nil
+}
+
+// Publications returns publications created by Reader sorted by name.
+func (a *Admin) Publications(ctx context.Context) ([]Publication, error) {
+	query := `SELECT p.pubname,
+			COALESCE(ARRAY(SELECT t.schemaname || '.' || t.tablename FROM pg_publication_tables t
+				WHERE t.pubname = p.pubname ORDER BY 1), '{}')
+		FROM pg_publication p
+		WHERE obj_description(p.oid, 'pg_publication') = $1
+		ORDER BY p.pubname`
+
+	rows, err := a.pool.Query(ctx, query, publicationComment)
+	if err != nil {
+		return nil, fmt.Errorf("pool.Query: %w", err)
+	}
+
+	publications, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (Publication, error) {
+		var publication Publication
+
+		if err := row.Scan(&publication.Name, &publication.Tables); err != nil {
+			return publication, fmt.Errorf("row.Scan: %w", err)
+		}
+
+		return publication, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pgx.CollectRows: %w", err)
+	}
+
+	return publications, nil
+}
+
+// DropPublication drops the publication created by Reader.
+// returns an error if
+// - the publication does not exist
+// - the publication is not created by Reader.
+func (a *Admin) DropPublication(ctx context.Context, name string) error {
+	var owned bool
+
+	err := a.pool.QueryRow(ctx, `SELECT COALESCE(obj_description(oid, 'pg_publication') = $2, false)
+		FROM pg_publication WHERE pubname = $1`, name, publicationComment).Scan(&owned)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("%w: %s", ErrPublicationNotFound, name)
+		}
+		return fmt.Errorf("pool.QueryRow: %w", err)
+	}
+
+	if !owned {
+		return fmt.Errorf("%w: %s", ErrPublicationNotOwned, name)
+	}
+
+	if _, err := a.pool.Exec(ctx, "DROP PUBLICATION "+pgx.Identifier{name}.Sanitize()); err != nil {
+		return fmt.Errorf("pool.Exec: %w", err)
+	}
+
+	return nil
+}
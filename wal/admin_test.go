@@ -0,0 +1,184 @@
+package wal_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/internal/fakes"
+	"github.com/nikolayk812/pgx-outbox/wal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *ReaderTestSuite) TestAdmin() {
+	t := suite.T()
+
+	const (
+		publication = "publication_admin"
+		slot        = "slot_admin"
+	)
+
+	admin, err := wal.NewAdmin(suite.pool)
+	require.NoError(t, err)
+
+	reader, err := wal.NewReader(suite.readerConnStr, outboxTable, publication, slot, wal.WithPermanentSlot())
+	require.NoError(t, err)
+
+	msgCh, _, err := reader.Start(ctx)
+	require.NoError(t, err)
+
+	_, err = suite.write(fakes.FakeMessage())
+	require.NoError(t, err)
+	suite.receive(msgCh)
+
+	// THEN the slot and the publication are listed
+	slots, err := admin.Slots(ctx)
+	require.NoError(t, err)
+
+	adminSlot, ok := findSlot(slots, slot)
+	require.True(t, ok)
+	assert.Equal(t, "pgoutput", adminSlot.Plugin)
+	assert.True(t, adminSlot.Active)
+	assert.False(t, adminSlot.Temporary)
+
+	publications, err := admin.Publications(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, publications, wal.Publication{Name: publication, Tables: []string{"public." + outboxTable}})
+
+	// AND the active slot is not dropped
+	require.ErrorIs(t, admin.DropSlot(ctx, slot), wal.ErrReplicationSlotActive)
+
+	// WHEN the reader is closed
+	reader.Close()
+
+	require.Eventually(t, func() bool {
+		return admin.DropSlot(ctx, slot) == nil
+	}, 10*time.Second, 100*time.Millisecond)
+	require.ErrorIs(t, admin.DropSlot(ctx, slot), wal.ErrReplicationSlotNotFound)
+
+	require.NoError(t, admin.DropPublication(ctx, publication))
+	require.ErrorIs(t, admin.DropPublication(ctx, publication), wal.ErrPublicationNotFound)
+
+	// AND publications not created by the reader are not dropped
+	_, err = suite.pool.Exec(ctx, "CREATE PUBLICATION publication_foreign")
+	require.NoError(t, err)
+	require.ErrorIs(t, admin.DropPublication(ctx, "publication_foreign"), wal.ErrPublicationNotOwned)
+}
+
+func (suite *ReaderTestSuite) TestMonitor_Check() {
+	t := suite.T()
+
+	const slot = "slot_monitor"
+
+	admin, err := wal.NewAdmin(suite.pool)
+	require.NoError(t, err)
+
+	// GIVEN an abandoned permanent slot retaining WAL
+	_, err = suite.pool.Exec(ctx, "SELECT pg_create_logical_replication_slot($1, 'pgoutput')", slot)
+	require.NoError(t, err)
+
+	_, err = suite.write(fakes.FakeMessage())
+	require.NoError(t, err)
+
+	var alerted []string
+
+	monitor, err := wal.NewMonitor(admin, 1, wal.WithMonitorSlots(slot), wal.WithDropExceeded(),
+		wal.WithAlert(func(_ context.Context, slot wal.Slot, dropped bool) {
+			assert.True(t, dropped)
+			alerted = append(alerted, slot.Name)
+		}))
+	require.NoError(t, err)
+
+	// WHEN
+	exceeded, err := monitor.Check(ctx)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, exceeded, 1)
+	assert.Equal(t, slot, exceeded[0].Name)
+	assert.Equal(t, []string{slot}, alerted)
+
+	slots, err := admin.Slots(ctx)
+	require.NoError(t, err)
+	_, ok := findSlot(slots, slot)
+	assert.False(t, ok)
+}
+
+func TestNewMonitor(t *testing.T) {
+	t.Parallel()
+
+	_, err := wal.NewAdmin(nil)
+	require.ErrorIs(t, err, outbox.ErrPoolNil)
+
+	tests := []struct {
+		name        string
+		admin       *wal.Admin
+		maxRetained int64
+		options     []wal.MonitorOption
+		wantErr     error
+	}{
+		{
+			name:        "nil admin",
+			maxRetained: 1,
+			wantErr:     wal.ErrAdminNil,
+		},
+		{
+			name:        "zero threshold",
+			admin:       &wal.Admin{},
+			maxRetained: 0,
+			wantErr:     wal.ErrRetainedThresholdInvalid,
+		},
+		{
+			name:        "zero interval",
+			admin:       &wal.Admin{},
+			maxRetained: 1,
+			options:     []wal.MonitorOption{wal.WithMonitorInterval(0)},
+			wantErr:     wal.ErrMonitorIntervalInvalid,
+		},
+		{
+			name:        "drop exceeded without slots",
+			admin:       &wal.Admin{},
+			maxRetained: 1,
+			options:     []wal.MonitorOption{wal.WithDropExceeded()},
+			wantErr:     wal.ErrMonitorSlotsRequired,
+		},
+		{
+			name:        "with all options",
+			admin:       &wal.Admin{},
+			maxRetained: 1 << 30,
+			options: []wal.MonitorOption{
+				wal.WithMonitorInterval(time.Second),
+				wal.WithMonitorSlots("slot"),
+				wal.WithDropExceeded(),
+				wal.WithAlert(func(context.Context, wal.Slot, bool) {}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			monitor, err := wal.NewMonitor(tt.admin, tt.maxRetained, tt.options...)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, monitor)
+		})
+	}
+}
+
+func findSlot(slots []wal.Slot, name string) (wal.Slot, bool) {
+	for _, slot := range slots {
+		if slot.Name == name {
+			return slot, true
+		}
+	}
+
+	return wal.Slot{}, false
+}
@@ -13,4 +13,39 @@ var (
 	ErrTableNotFound = errors.New("table does not exist")
 
 	ErrUnexpectedMessageType = errors.New("unexpected message type")
+
+	ErrReconnectPermanentSlotRequired = errors.New("reconnect requires permanent replication slot")
+	ErrReconnectBackoffInvalid        = errors.New("reconnect backoff is invalid")
+	ErrReconnectAttemptsExhausted     = errors.New("reconnect attempts exhausted")
+
+	ErrManualAckDisabled = errors.New("manual ack is disabled")
+	ErrAckLSNInvalid     = errors.New("ack lsn is beyond the last emitted message")
+
+	ErrReaderClosed = errors.New("reader is closed")
+
+	ErrChannelFull                 = errors.New("message channel is full")
+	ErrBackpressurePolicyInvalid   = errors.New("backpressure policy is invalid")
+	ErrBackpressureDropUnsupported = errors.New("backpressure drop is unsupported by logical messages")
+
+	ErrProtocolVersionInvalid = errors.New("protocol version is invalid")
+
+	ErrOperationInvalid = errors.New("operation is invalid")
+
+	ErrReplicationSlotNotFound = errors.New("replication slot is not found")
+	ErrPublicationNotFound     = errors.New("publication is not found")
+	ErrPublicationNotOwned     = errors.New("publication is not created by wal.Reader")
+
+	ErrAdminNil                 = errors.New("admin is nil")
+	ErrRetainedThresholdInvalid = errors.New("retained WAL threshold must be GT 0")
+	ErrMonitorIntervalInvalid   = errors.New("monitor interval must be GT 0")
+	ErrMonitorSlotsRequired     = errors.New("drop exceeded requires monitor slots")
+
+	ErrMarkPublishedUnsupported = errors.New("mark published is unsupported by the reader")
+
+	ErrHandlersEmpty   = errors.New("handlers are empty")
+	ErrHandlerNil      = errors.New("handler is nil")
+	ErrHandlerNotFound = errors.New("handler is not found")
+
+	ErrColumnUnchangedToast = errors.New("column is unchanged TOAST value")
+	ErrColumnFormatInvalid  = errors.New("column format is invalid")
 )
@@ -0,0 +1,105 @@
+package wal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Postgres 15 supports row filters and column lists of publications.
+const publicationFilterServerVersion = 150000
+
+// serverVersion returns server_version_num, i.e. 170005 for Postgres 17.5.
+func (r *Reader) serverVersion(ctx context.Context) (int, error) {
+	result := r.getConn().Exec(ctx, "SHOW server_version_num")
+	defer closeResource("server_version_query_result", result)
+
+	row, err := toRow(result)
+	if err != nil {
+		return 0, fmt.Errorf("toRow: %w", err)
+	}
+
+	if len(row) == 0 {
+		return 0, fmt.Errorf("server_version_num is absent")
+	}
+
+	version, err := strconv.Atoi(string(row[0]))
+	if err != nil {
+		return 0, fmt.Errorf("strconv.Atoi: %w", err)
+	}
+
+	return version, nil
+}
+
+// tableSpec returns the table of the publication with the column list and the row filter, i.e.
+// outbox_messages (id, broker, topic, payload) WHERE (broker IN ('sns'))
+func (r *Reader) tableSpec(table string) string {
+	spec := table
+
+	if len(r.columns) > 0 {
+		spec += " (" + strings.Join(r.columns, ", ") + ")"
+	}
+
+	var conditions []string
+	if len(r.filter.Brokers) > 0 {
+		conditions = append(conditions, "broker IN ("+quoteLiterals(r.filter.Brokers)+")")
+	}
+	if len(r.filter.Topics) > 0 {
+		conditions = append(conditions, "topic IN ("+quoteLiterals(r.filter.Topics)+")")
+	}
+
+	if len(conditions) > 0 {
+		spec += " WHERE (" + strings.Join(conditions, " AND ") + ")"
+	}
+
+	return spec
+}
+
+func quoteLiterals(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, "'"+strings.ReplaceAll(value, "'", "''")+"'")
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// accept filters messages client-side, for Postgres before 15, existing publications and logical decoding messages.
+// Rows without broker or topic columns, i.e. the old key of deleted rows, are accepted.
+func (r *Reader) accept(message Message) bool {
+	row := message.RawMessage
+	if row == nil {
+		row = message.Old
+	}
+
+	if broker, ok := row["broker"].(string); ok && len(r.filter.Brokers) > 0 {
+		if !slices.Contains(r.filter.Brokers, broker) {
+			return false
+		}
+	}
+
+	if topic, ok := row["topic"].(string); ok && len(r.filter.Topics) > 0 {
+		if !slices.Contains(r.filter.Topics, topic) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// project keeps the columns of WithColumns option only, client-side for the same reasons as accept.
+func (r *Reader) project(row RawMessage) RawMessage {
+	if len(r.columns) == 0 || row == nil {
+		return row
+	}
+
+	for column := range row {
+		if !slices.Contains(r.columns, column) {
+			delete(row, column)
+		}
+	}
+
+	return row
+}
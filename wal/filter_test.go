@@ -0,0 +1,75 @@
+package wal_test
+
+import (
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/nikolayk812/pgx-outbox/internal/fakes"
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/nikolayk812/pgx-outbox/wal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *ReaderTestSuite) TestReader_Filter() {
+	t := suite.T()
+
+	const publication = "publication_filter"
+
+	columns := []string{"id", "broker", "topic", "payload"}
+
+	reader, err := wal.NewReader(suite.readerConnStr, outboxTable, publication, "slot_filter",
+		wal.WithFilter(types.MessageFilter{Brokers: []string{"sns", "o'reilly"}}),
+		wal.WithColumns(columns...))
+	require.NoError(t, err)
+	defer reader.Close()
+
+	msgCh, _, err := reader.Start(ctx)
+	require.NoError(t, err)
+
+	// THEN the publication filters rows and columns server-side
+	var rowFilter string
+	var attNames []string
+	require.NoError(t, suite.pool.QueryRow(ctx,
+		"SELECT rowfilter, attnames FROM pg_publication_tables WHERE pubname = $1", publication).
+		Scan(&rowFilter, &attNames))
+	assert.Contains(t, rowFilter, "'sns'")
+	assert.Contains(t, rowFilter, "'o''reilly'")
+	assert.ElementsMatch(t, columns, attNames)
+
+	// GIVEN
+	skipped := fakes.FakeMessage()
+	skipped.Broker = "sqs"
+
+	msg1 := fakes.FakeMessage()
+	msg1.Broker = "sns"
+
+	msg2 := fakes.FakeMessage()
+	msg2.Broker = "o'reilly"
+
+	for _, message := range []types.Message{skipped, msg1, msg2} {
+		_, err := suite.write(message)
+		require.NoError(t, err)
+	}
+
+	// WHEN
+	actual := []wal.Message{receiveMessage(t, msgCh), receiveMessage(t, msgCh)}
+
+	// THEN
+	for idx, expected := range []types.Message{msg1, msg2} {
+		assert.ElementsMatch(t, columns, slices.Collect(maps.Keys(actual[idx].RawMessage)))
+
+		message, err := actual[idx].ToOutboxMessage()
+		require.NoError(t, err)
+		assert.Equal(t, expected.Broker, message.Broker)
+		assert.Equal(t, expected.Topic, message.Topic)
+		assert.JSONEq(t, string(expected.Payload), string(message.Payload))
+	}
+
+	select {
+	case message := <-msgCh:
+		t.Fatalf("unexpected message: %v", message)
+	case <-time.After(time.Second):
+	}
+}
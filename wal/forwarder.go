@@ -0,0 +1,170 @@
+package wal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/types"
+)
+
+// Forwarder publishes messages streamed by Reader and then acknowledges them by Reader.Ack,
+// so the replication slot advances only after successful publishing.
+// It implements outbox.Forwarder.
+type Forwarder struct {
+	reader    *Reader
+	publisher outbox.Publisher
+	marker    outbox.Reader
+
+	pending []Message // received but not acknowledged yet, i.e. due to publishing error
+}
+
+// NewForwarder requires the reader with WithManualAck option and without operations other than OperationInsert,
+// WithMarkPublished option requires the reader watching a single table without WithLogicalMessages option.
+func NewForwarder(reader *Reader, publisher outbox.Publisher, opts ...ForwardOption) (*Forwarder, error) {
+	if reader == nil {
+		return nil, outbox.ErrReaderNil
+	}
+	if publisher == nil {
+		return nil, outbox.ErrPublisherNil
+	}
+	if !reader.manualAck {
+		return nil, ErrManualAckDisabled
+	}
+	if slices.ContainsFunc(reader.operations, func(operation Operation) bool { return operation != OperationInsert }) {
+		return nil, fmt.Errorf("%w: forwarder supports inserts only", ErrOperationInvalid)
+	}
+
+	f := &Forwarder{
+		reader:    reader,
+		publisher: publisher,
+	}
+
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	// IDs of messages from several tables would be marked in the marker table, even unrelated or delayed ones
+	if f.marker != nil && len(reader.tables) > 1 {
+		return nil, fmt.Errorf("%w: reader watches %d tables", ErrMarkPublishedUnsupported, len(reader.tables))
+	}
+	// IDs of logical decoding messages are LSNs, not IDs of rows
+	if f.marker != nil && reader.logicalPrefix != "" {
+		return nil, fmt.Errorf("%w: reader reads logical decoding messages", ErrMarkPublishedUnsupported)
+	}
+
+	return f, nil
+}
+
+// Forward waits for at least one message from the reader, takes up to the limit of already received messages,
+// publishes them, marks them as published if WithMarkPublished option is set and then acknowledges them.
+// The reader is started by the first Forward call if it is not started yet,
+// then ctx of that call governs the replication stream, so it is better to start the reader beforehand.
+// If a message fails to be published, the function returns an error immediately,
+// the failed and the following messages are published again on the next call.
+// returns an error if
+// - limit is LTE 0
+// - the reader is closed or fails
+// - publishing, marking or acknowledging fails.
+func (f *Forwarder) Forward(ctx context.Context, limit int) (types.ForwardOutput, error) {
+	var fs types.ForwardOutput
+
+	if limit <= 0 {
+		return fs, fmt.Errorf("limit must be GT 0, got %d", limit)
+	}
+
+	if err := f.receive(ctx, limit); err != nil {
+		return fs, fmt.Errorf("receive: %w", err)
+	}
+
+	for idx, walMessage := range f.pending[:min(limit, len(f.pending))] {
+		message, err := walMessage.ToOutboxMessage()
+		if err != nil {
+			return fs, fmt.Errorf("ToOutboxMessage index[%d] lsn[%s]: %w", idx, walMessage.LSN, err)
+		}
+		fs.Read = append(fs.Read, message)
+	}
+
+	for idx, message := range fs.Read {
+		if err := f.publisher.Publish(ctx, message); err != nil {
+			return fs, f.ack(ctx, &fs, idx,
+				fmt.Errorf("publisher.Publish index[%d] topic[%s] id[%d]: %w", idx, message.Topic, message.ID, err))
+		}
+		fs.PublishedIDs = append(fs.PublishedIDs, message.ID)
+	}
+
+	return fs, f.ack(ctx, &fs, len(fs.Read), nil)
+}
+
+// receive fills pending messages up to the limit, it blocks until at least one message is pending.
+func (f *Forwarder) receive(ctx context.Context, limit int) error {
+	messageCh, errorCh, err := f.reader.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("reader.Start: %w", err)
+	}
+
+	for len(f.pending) == 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case message, ok := <-messageCh:
+			if !ok {
+				return readerClosedError(errorCh)
+			}
+			f.pending = append(f.pending, message)
+		}
+	}
+
+	for len(f.pending) < limit {
+		select {
+		case message, ok := <-messageCh:
+			if !ok {
+				return nil // pending messages are forwarded, the error is returned on the next call
+			}
+			f.pending = append(f.pending, message)
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// ack marks and acknowledges the first count published messages, so they are removed from pending.
+// cause is returned as is if there is nothing to acknowledge.
+func (f *Forwarder) ack(ctx context.Context, fs *types.ForwardOutput, count int, cause error) error {
+	if count == 0 {
+		return cause
+	}
+
+	ids := types.Messages(fs.Read[:count]).IDs()
+
+	if f.marker != nil {
+		// if it fails here, messages would be published again on the next call
+		ackedIDs, err := f.marker.Ack(ctx, ids)
+		if err != nil {
+			return errors.Join(cause, fmt.Errorf("marker.Ack count[%d]: %w", len(ids), err))
+		}
+		fs.AckedIDs = ackedIDs
+	} else {
+		fs.AckedIDs = ids
+	}
+
+	if err := f.reader.Ack(f.pending[count-1].LSN); err != nil {
+		return errors.Join(cause, fmt.Errorf("reader.Ack: %w", err))
+	}
+
+	f.pending = f.pending[count:]
+
+	return cause
+}
+
+func readerClosedError(errorCh <-chan error) error {
+	if err, ok := <-errorCh; ok && err != nil {
+		return fmt.Errorf("%w: %w", ErrReaderClosed, err)
+	}
+
+	return ErrReaderClosed
+}
@@ -0,0 +1,171 @@
+package wal_test
+
+import (
+	"errors"
+	"time"
+
+	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/internal/fakes"
+	"github.com/nikolayk812/pgx-outbox/internal/mocks"
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/nikolayk812/pgx-outbox/wal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *ReaderTestSuite) TestForwarder_Forward() {
+	t := suite.T()
+
+	const slot = "slot_forwarder"
+	defer suite.dropReplicationSlot(slot)
+
+	reader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", slot,
+		wal.WithPermanentSlot(), wal.WithManualAck())
+	require.NoError(t, err)
+	defer reader.Close()
+
+	_, _, err = reader.Start(ctx)
+	require.NoError(t, err)
+
+	marker, err := outbox.NewReader(outboxTable, suite.pool)
+	require.NoError(t, err)
+
+	publishErr := errors.New("publish failed")
+
+	publisherMock := mocks.NewPublisher(t)
+	publisherMock.On("Publish", ctx, mock.Anything).Return(nil).Once()
+	publisherMock.On("Publish", ctx, mock.Anything).Return(publishErr).Once()
+	publisherMock.On("Publish", ctx, mock.Anything).Return(nil).Once()
+
+	forwarder, err := wal.NewForwarder(reader, publisherMock, wal.WithMarkPublished(marker))
+	require.NoError(t, err)
+
+	// GIVEN
+	msg1 := fakes.FakeMessage()
+	msg2 := fakes.FakeMessage()
+
+	ids, err := suite.writeBatch([]types.Message{msg1, msg2})
+	require.NoError(t, err)
+
+	time.Sleep(500 * time.Millisecond) // for both messages to be streamed
+
+	// WHEN the second message fails to be published
+	fs, err := forwarder.Forward(ctx, 10)
+
+	// THEN the first message is published and marked
+	require.ErrorIs(t, err, publishErr)
+	assertEqualMessages(t, []types.Message{msg1, msg2}, fs.Read)
+	assert.Equal(t, ids[:1], fs.PublishedIDs)
+	assert.Equal(t, ids[:1], fs.AckedIDs)
+
+	// WHEN forwarded again
+	fs, err = forwarder.Forward(ctx, 10)
+
+	// THEN only the second message is published and marked
+	require.NoError(t, err)
+	assertEqualMessages(t, []types.Message{msg2}, fs.Read)
+	assert.Equal(t, ids[1:], fs.PublishedIDs)
+	assert.Equal(t, ids[1:], fs.AckedIDs)
+
+	var unpublished int
+	require.NoError(t, suite.pool.QueryRow(ctx,
+		"SELECT COUNT(*) FROM outbox_messages WHERE id = ANY($1) AND published_at IS NULL", ids).Scan(&unpublished))
+	assert.Zero(t, unpublished)
+}
+
+// TestForwarder_New is just to increase coverage.
+func (suite *ReaderTestSuite) TestForwarder_New() {
+	manualAckReader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", "slot",
+		wal.WithManualAck())
+	suite.noError(err)
+
+	autoAckReader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", "slot")
+	suite.noError(err)
+
+	updatesReader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", "slot",
+		wal.WithManualAck(), wal.WithOperations(wal.OperationInsert, wal.OperationUpdate))
+	suite.noError(err)
+
+	tablesReader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", "slot",
+		wal.WithManualAck(), wal.WithTables("outbox_messages_billing"))
+	suite.noError(err)
+
+	logicalReader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", "slot",
+		wal.WithManualAck(), wal.WithLogicalMessages("outbox"))
+	suite.noError(err)
+
+	marker, err := outbox.NewReader(outboxTable, suite.pool)
+	suite.noError(err)
+
+	tests := []struct {
+		name      string
+		reader    *wal.Reader
+		publisher outbox.Publisher
+		options   []wal.ForwardOption
+		wantErr   error
+	}{
+		{
+			name:      "nil reader",
+			publisher: &mocks.Publisher{},
+			wantErr:   outbox.ErrReaderNil,
+		},
+		{
+			name:    "nil publisher",
+			reader:  manualAckReader,
+			wantErr: outbox.ErrPublisherNil,
+		},
+		{
+			name:      "manual ack disabled",
+			reader:    autoAckReader,
+			publisher: &mocks.Publisher{},
+			wantErr:   wal.ErrManualAckDisabled,
+		},
+		{
+			name:      "operations other than insert",
+			reader:    updatesReader,
+			publisher: &mocks.Publisher{},
+			wantErr:   wal.ErrOperationInvalid,
+		},
+		{
+			name:      "mark published with several tables",
+			reader:    tablesReader,
+			publisher: &mocks.Publisher{},
+			options:   []wal.ForwardOption{wal.WithMarkPublished(marker)},
+			wantErr:   wal.ErrMarkPublishedUnsupported,
+		},
+		{
+			name:      "mark published with logical messages",
+			reader:    logicalReader,
+			publisher: &mocks.Publisher{},
+			options:   []wal.ForwardOption{wal.WithMarkPublished(marker)},
+			wantErr:   wal.ErrMarkPublishedUnsupported,
+		},
+		{
+			name:      "several tables without mark published",
+			reader:    tablesReader,
+			publisher: &mocks.Publisher{},
+		},
+		{
+			name:      "valid",
+			reader:    manualAckReader,
+			publisher: &mocks.Publisher{},
+			options:   []wal.ForwardOption{wal.WithMarkPublished(marker)},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			t := suite.T()
+
+			forwarder, err := wal.NewForwarder(tt.reader, tt.publisher, tt.options...)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, forwarder)
+		})
+	}
+}
@@ -60,9 +60,13 @@ func (r *Reader) handlePrimaryKeepalive(data []byte) error {
 	}
 
 	if pkm.ServerWALEnd > r.lastReceivedLSN {
+		r.lastReceivedLSN = pkm.ServerWALEnd
+	}
+
+	// with manual ack, only when all emitted messages are acknowledged
+	if !r.inTransaction && (!r.manualAck || r.lastProcessedLSN.Load() >= r.lastEmittedLSN.Load()) {
 		// looks weird but Logical replication clients don’t need to process every byte of the WAL,
 		// as they only care about specific changes (e.g., those related to a publication).
-		r.lastReceivedLSN = pkm.ServerWALEnd
 		r.updateLastProcessedLSN(pkm.ServerWALEnd)
 	}
 
@@ -73,7 +77,7 @@ func (r *Reader) handlePrimaryKeepalive(data []byte) error {
 	return nil
 }
 
-func (r *Reader) handleXLogData(data []byte) error {
+func (r *Reader) handleXLogData(ctx context.Context, data []byte) error {
 	xld, err := pglogrepl.ParseXLogData(data)
 	if err != nil {
 		return fmt.Errorf("pglogrepl.ParseXLogData: %w", err)
@@ -86,66 +90,519 @@ func (r *Reader) handleXLogData(data []byte) error {
 	// log.Printf("XLogData => WALStart %s ServerWALEnd %s ServerTime %s WALData:\n",
 	//	xld.WALStart, xld.ServerWALEnd, xld.ServerTime)
 
-	if err := r.processV2(xld.WALData); err != nil {
+	if err := r.processV2(ctx, xld.WALData); err != nil {
 		return fmt.Errorf("processV2: %w", err)
 	}
 
-	r.updateLastProcessedLSN(xld.ServerWALEnd)
-
 	return nil
 }
 
-func (r *Reader) processV2(walData []byte) error {
-	logicalMsg, err := pglogrepl.ParseV2(walData, false)
+//nolint:cyclop,funlen
+func (r *Reader) processV2(ctx context.Context, walData []byte) error {
+	if isTwoPhaseMessage(walData) {
+		if err := r.processTwoPhase(ctx, walData); err != nil {
+			return fmt.Errorf("processTwoPhase: %w", err)
+		}
+		return nil
+	}
+
+	logicalMsg, err := pglogrepl.ParseV2(walData, r.inStream)
 	if err != nil {
 		return fmt.Errorf("pglogrepl.ParseV2: %w", err)
 	}
 
 	switch msg := logicalMsg.(type) {
+	case *pglogrepl.BeginMessage:
+		r.inTransaction = true
+		r.tx = txInfo{xid: msg.Xid, commitLSN: msg.FinalLSN, commitTime: msg.CommitTime}
+
+	case *pglogrepl.CommitMessage:
+		if err := r.commit(ctx, msg.TransactionEndLSN); err != nil {
+			return fmt.Errorf("commit: %w", err)
+		}
+
+	case *pglogrepl.StreamStartMessageV2:
+		r.inStream = true
+		r.streamXid = msg.Xid
+		if _, ok := r.streams[msg.Xid]; !ok {
+			r.streams[msg.Xid] = newTxBuffer(r.spillThreshold)
+		}
+
+	case *pglogrepl.StreamStopMessageV2:
+		r.inStream = false
+
+	case *pglogrepl.StreamCommitMessageV2:
+		buffer, ok := r.streams[msg.Xid]
+		if !ok {
+			return fmt.Errorf("stream commit: unknown xid[%d]", msg.Xid)
+		}
+		delete(r.streams, msg.Xid)
+
+		r.tx = txInfo{xid: msg.Xid, commitLSN: msg.CommitLSN, commitTime: msg.CommitTime}
+
+		if err := r.replay(ctx, buffer, msg.TransactionEndLSN); err != nil {
+			return fmt.Errorf("replay xid[%d]: %w", msg.Xid, err)
+		}
+
+	case *pglogrepl.StreamAbortMessageV2:
+		buffer, ok := r.streams[msg.Xid]
+		if !ok {
+			return nil
+		}
+
+		if msg.SubXid != msg.Xid {
+			buffer.abort(msg.SubXid)
+			return nil
+		}
+
+		delete(r.streams, msg.Xid)
+		closeBuffer(buffer)
+
 	case *pglogrepl.RelationMessageV2:
 		r.relations[msg.RelationID] = msg
 
 	case *pglogrepl.InsertMessageV2:
-		rawMessage, err := r.handleInsert(msg)
+		if !r.operationEnabled(OperationInsert) {
+			return nil
+		}
+
+		if err := r.processChange(ctx, msg, msg.Xid, walData); err != nil {
+			return fmt.Errorf("processChange: %w", err)
+		}
+
+	case *pglogrepl.UpdateMessageV2:
+		if !r.operationEnabled(OperationUpdate) {
+			return nil
+		}
+
+		if err := r.processChange(ctx, msg, msg.Xid, walData); err != nil {
+			return fmt.Errorf("processChange: %w", err)
+		}
+
+	case *pglogrepl.DeleteMessageV2:
+		if !r.operationEnabled(OperationDelete) {
+			return nil
+		}
+
+		if err := r.processChange(ctx, msg, msg.Xid, walData); err != nil {
+			return fmt.Errorf("processChange: %w", err)
+		}
+
+	case *pglogrepl.TruncateMessageV2:
+		if !r.operationEnabled(OperationTruncate) {
+			return nil
+		}
+
+		if err := r.processChange(ctx, msg, msg.Xid, walData); err != nil {
+			return fmt.Errorf("processChange: %w", err)
+		}
+
+	case *pglogrepl.LogicalDecodingMessageV2:
+		if !r.acceptLogicalMessage(msg) {
+			return nil
+		}
+
+		if err := r.processChange(ctx, msg, msg.Xid, walData); err != nil {
+			return fmt.Errorf("processChange: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// processChange buffers the change of a streamed or prepared transaction until commit, otherwise emits it.
+// subXid is set for changes of streamed transactions only.
+func (r *Reader) processChange(ctx context.Context, msg pglogrepl.Message, subXid uint32, walData []byte) error {
+	if r.inStream {
+		if err := r.streams[r.streamXid].add(subXid, true, walData); err != nil {
+			return fmt.Errorf("stream add: %w", err)
+		}
+		return nil
+	}
+
+	if r.preparing != nil {
+		if err := r.preparing.add(0, false, walData); err != nil {
+			return fmt.Errorf("prepare add: %w", err)
+		}
+		return nil
+	}
+
+	return r.emitChange(ctx, msg)
+}
+
+//nolint:cyclop
+func (r *Reader) emitChange(ctx context.Context, msg pglogrepl.Message) error {
+	var (
+		message = Message{Operation: OperationInsert}
+		err     error
+	)
+
+	switch m := msg.(type) {
+	case *pglogrepl.InsertMessageV2:
+		message.RawMessage, err = r.handleInsert(m)
 		if err != nil {
 			return fmt.Errorf("handleInsert: %w", err)
 		}
+		message.Schema, message.Table = r.relationName(m.RelationID)
+	case *pglogrepl.UpdateMessageV2:
+		message, err = r.handleUpdate(m)
+		if err != nil {
+			return fmt.Errorf("handleUpdate: %w", err)
+		}
+		message.Schema, message.Table = r.relationName(m.RelationID)
+	case *pglogrepl.DeleteMessageV2:
+		message, err = r.handleDelete(m)
+		if err != nil {
+			return fmt.Errorf("handleDelete: %w", err)
+		}
+		message.Schema, message.Table = r.relationName(m.RelationID)
+	case *pglogrepl.TruncateMessageV2:
+		// one message per truncated table
+		for _, relationID := range m.RelationIDs {
+			schema, table := r.relationName(relationID)
+			if err := r.hold(ctx, Message{Operation: OperationTruncate, Schema: schema, Table: table}); err != nil {
+				return fmt.Errorf("hold: %w", err)
+			}
+		}
+		return nil
+	case *pglogrepl.LogicalDecodingMessageV2:
+		message.RawMessage, err = logicalRawMessage(m)
+		if err != nil {
+			return fmt.Errorf("logicalRawMessage: %w", err)
+		}
+	default:
+		return fmt.Errorf("unexpected message type[%T]", msg)
+	}
 
-		select {
-		case r.messageCh <- rawMessage:
-			return nil
-		default:
-			return fmt.Errorf("messageCh channel is full")
+	if !r.accept(message) {
+		return nil
+	}
+
+	// delayed messages stay unpublished in the outbox table to be published by outbox.Forwarder
+	if message.Operation == OperationInsert && message.delayed(time.Now()) {
+		return nil
+	}
+
+	message.RawMessage = r.project(message.RawMessage)
+	message.Old = r.project(message.Old)
+
+	return r.hold(ctx, message)
+}
+
+// hold emits the held message, as it is not the last one of the transaction, and holds the new one.
+func (r *Reader) hold(ctx context.Context, message Message) error {
+	if err := r.emitHeld(ctx, r.lastCommitLSN); err != nil {
+		return fmt.Errorf("emitHeld: %w", err)
+	}
+
+	message.Xid = r.tx.xid
+	message.CommitLSN = r.tx.commitLSN
+	message.CommitTime = r.tx.commitTime
+	r.held = &message
+
+	return nil
+}
+
+// relationName returns schema and table of the relation, empty if the relation is unknown.
+func (r *Reader) relationName(relationID uint32) (string, string) {
+	rel, ok := r.relations[relationID]
+	if !ok {
+		return "", ""
+	}
+
+	return rel.Namespace, rel.RelationName
+}
+
+// acceptLogicalMessage reports whether the message is emitted by outbox.NewLogicalMessageWriter with the prefix.
+func (r *Reader) acceptLogicalMessage(msg *pglogrepl.LogicalDecodingMessageV2) bool {
+	return r.logicalPrefix != "" && msg.Transactional && msg.Prefix == r.logicalPrefix
+}
+
+// commit emits the last message of the transaction.
+func (r *Reader) commit(ctx context.Context, endLSN pglogrepl.LSN) error {
+	r.inTransaction = false
+	r.lastCommitLSN = endLSN
+
+	if r.held != nil {
+		r.held.Last = true
+	}
+
+	if err := r.emitHeld(ctx, endLSN); err != nil {
+		return fmt.Errorf("emitHeld: %w", err)
+	}
+
+	if !r.manualAck {
+		r.updateLastProcessedLSN(endLSN)
+	}
+
+	return nil
+}
+
+// replay emits buffered messages of the streamed or prepared transaction once it is committed.
+func (r *Reader) replay(ctx context.Context, buffer *txBuffer, endLSN pglogrepl.LSN) error {
+	defer closeBuffer(buffer)
+
+	if err := buffer.replay(func(record txRecord) error {
+		logicalMsg, err := pglogrepl.ParseV2(record.data, record.inStream)
+		if err != nil {
+			return fmt.Errorf("pglogrepl.ParseV2: %w", err)
+		}
+
+		return r.emitChange(ctx, logicalMsg)
+	}); err != nil {
+		return fmt.Errorf("buffer.replay: %w", err)
+	}
+
+	return r.commit(ctx, endLSN)
+}
+
+func (r *Reader) processTwoPhase(ctx context.Context, walData []byte) error {
+	switch walData[0] {
+	case messageTypeBeginPrepare:
+		if _, err := parsePrepare(walData); err != nil {
+			return fmt.Errorf("parsePrepare: %w", err)
+		}
+		r.inTransaction = true
+		r.preparing = newTxBuffer(r.spillThreshold)
+
+	case messageTypePrepare, messageTypeStreamPrepare:
+		msg, err := parsePrepare(walData)
+		if err != nil {
+			return fmt.Errorf("parsePrepare: %w", err)
+		}
+
+		buffer := r.preparing
+		r.preparing = nil
+		r.inTransaction = false
+
+		if walData[0] == messageTypeStreamPrepare {
+			buffer = r.streams[msg.Xid]
+			delete(r.streams, msg.Xid)
+		}
+		if buffer == nil {
+			return fmt.Errorf("prepare: unknown gid[%s]", msg.GID)
+		}
+
+		r.prepared[msg.GID] = preparedTx{prepareLSN: msg.PrepareLSN, buffer: buffer}
+		r.updateHoldLSN()
+
+	case messageTypeCommitPrepared:
+		msg, err := parseCommitPrepared(walData)
+		if err != nil {
+			return fmt.Errorf("parseCommitPrepared: %w", err)
+		}
+
+		prepared, ok := r.prepared[msg.GID]
+		if !ok {
+			return fmt.Errorf("commit prepared: unknown gid[%s]", msg.GID)
+		}
+		delete(r.prepared, msg.GID)
+		r.updateHoldLSN()
+
+		r.tx = txInfo{xid: msg.Xid, commitLSN: msg.CommitLSN, commitTime: msg.CommitTime}
+
+		if err := r.replay(ctx, prepared.buffer, msg.EndLSN); err != nil {
+			return fmt.Errorf("replay gid[%s]: %w", msg.GID, err)
+		}
+
+	case messageTypeRollbackPrepared:
+		msg, err := parseRollbackPrepared(walData)
+		if err != nil {
+			return fmt.Errorf("parseRollbackPrepared: %w", err)
+		}
+
+		if prepared, ok := r.prepared[msg.GID]; ok {
+			delete(r.prepared, msg.GID)
+			closeBuffer(prepared.buffer)
+			r.updateHoldLSN()
 		}
 	}
 
 	return nil
 }
 
+// updateHoldLSN keeps the confirmed LSN before the earliest pending prepared transaction,
+// as Postgres does not send a prepared transaction again once its prepare is confirmed.
+func (r *Reader) updateHoldLSN() {
+	var hold pglogrepl.LSN
+
+	for _, prepared := range r.prepared {
+		if hold == 0 || prepared.prepareLSN < hold {
+			hold = prepared.prepareLSN
+		}
+	}
+
+	r.holdLSN.Store(uint64(hold))
+}
+
+// resetBuffers discards buffered transactions, they are sent again after restart of replication.
+func (r *Reader) resetBuffers() {
+	for xid, buffer := range r.streams {
+		closeBuffer(buffer)
+		delete(r.streams, xid)
+	}
+
+	for gid, prepared := range r.prepared {
+		closeBuffer(prepared.buffer)
+		delete(r.prepared, gid)
+	}
+
+	if r.preparing != nil {
+		closeBuffer(r.preparing)
+		r.preparing = nil
+	}
+
+	r.inStream = false
+	r.updateHoldLSN()
+}
+
+func closeBuffer(buffer *txBuffer) {
+	if err := buffer.close(); err != nil {
+		slog.Error("closeBuffer", "error", err)
+	}
+}
+
+// emitHeld sends the held message, if any, to the channel with the lsn according to the backpressure policy.
+func (r *Reader) emitHeld(ctx context.Context, lsn pglogrepl.LSN) error {
+	if r.held == nil {
+		return nil
+	}
+
+	message := *r.held
+	message.LSN = lsn
+
+	select {
+	case r.messageCh <- message:
+		r.held = nil
+		updateLSN(&r.lastEmittedLSN, lsn)
+		return nil
+	default:
+	}
+
+	switch r.backpressure {
+	case BackpressureError:
+		return ErrChannelFull
+	case BackpressureDrop:
+		// the message stays unpublished in the outbox table to be published by outbox.Forwarder
+		slog.Warn("wal.Reader dropped message", "slot", r.slot, "id", message.RawMessage["id"], "lsn", lsn)
+		r.held = nil
+		return nil
+	case BackpressureBlock:
+	}
+
+	return r.emitBlocking(ctx, message)
+}
+
+// emitBlocking waits for the consumer to free the channel,
+// meanwhile it keeps sending standby status updates, so the server does not time out the connection.
+func (r *Reader) emitBlocking(ctx context.Context, message Message) error {
+	for {
+		timer := time.NewTimer(time.Until(r.nextStandbyDeadline))
+
+		select {
+		case r.messageCh <- message:
+			timer.Stop()
+			r.held = nil
+			updateLSN(&r.lastEmittedLSN, message.LSN)
+			return nil
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-r.closeCh:
+			timer.Stop()
+			return nil
+		case <-timer.C:
+			if err := r.sendStatusUpdate(ctx); err != nil {
+				return fmt.Errorf("sendStatusUpdate: %w", err)
+			}
+		}
+	}
+}
+
 func (r *Reader) handleInsert(msg *pglogrepl.InsertMessageV2) (RawMessage, error) {
 	if msg.Tuple == nil {
 		return nil, fmt.Errorf("msg.Tuple is nil")
 	}
 
+	return r.decodeTuple(msg.RelationID, msg.Tuple)
+}
+
+func (r *Reader) handleUpdate(msg *pglogrepl.UpdateMessageV2) (Message, error) {
+	message := Message{Operation: OperationUpdate}
+
+	if msg.NewTuple == nil {
+		return message, fmt.Errorf("msg.NewTuple is nil")
+	}
+
+	newRow, err := r.decodeTuple(msg.RelationID, msg.NewTuple)
+	if err != nil {
+		return message, fmt.Errorf("decodeTuple[new]: %w", err)
+	}
+	message.RawMessage = newRow
+
+	// sent for REPLICA IDENTITY FULL or when the key is changed
+	if msg.OldTuple != nil {
+		oldRow, err := r.decodeTuple(msg.RelationID, msg.OldTuple)
+		if err != nil {
+			return message, fmt.Errorf("decodeTuple[old]: %w", err)
+		}
+		message.Old = oldRow
+
+		// unchanged TOAST values are not sent in the new row, but they are in the full old row
+		for name, value := range newRow {
+			if oldValue, ok := oldRow[name]; ok && value == UnchangedToast && oldValue != UnchangedToast {
+				newRow[name] = oldValue
+			}
+		}
+	}
+
+	return message, nil
+}
+
+func (r *Reader) handleDelete(msg *pglogrepl.DeleteMessageV2) (Message, error) {
+	message := Message{Operation: OperationDelete}
+
+	if msg.OldTuple == nil {
+		return message, fmt.Errorf("msg.OldTuple is nil")
+	}
+
+	oldRow, err := r.decodeTuple(msg.RelationID, msg.OldTuple)
+	if err != nil {
+		return message, fmt.Errorf("decodeTuple: %w", err)
+	}
+	message.Old = oldRow
+
+	return message, nil
+}
+
+func (r *Reader) decodeTuple(relationID uint32, tuple *pglogrepl.TupleData) (RawMessage, error) {
 	rawMessage := RawMessage{}
 
-	for idx, col := range msg.Tuple.Columns {
-		column, err := r.getRelationColumn(msg.RelationID, idx)
+	for idx, col := range tuple.Columns {
+		column, err := r.getRelationColumn(relationID, idx)
 		if err != nil {
-			return nil, fmt.Errorf("getRelationColumn[%d]: %w", msg.RelationID, err)
+			return nil, fmt.Errorf("getRelationColumn[%d]: %w", relationID, err)
 		}
 
 		switch col.DataType {
 		case 'n': // null
 			rawMessage[column.Name] = nil
-		case 'u': // TOAST unchanged
-			// For INSERT operations, this shouldn't happen
+		case 'u': // TOAST unchanged, the value is not sent
+			rawMessage[column.Name] = UnchangedToast
 		case 't': // text
-			val, err := r.decodeTextColumnData(col.Data, column.DataType)
+			val, err := r.decodeColumnData(col.Data, column.DataType, pgtype.TextFormatCode)
+			if err != nil {
+				return nil, fmt.Errorf("decodeColumnData[%s]: %w", column.Name, err)
+			}
+			rawMessage[column.Name] = val
+		case 'b': // binary, see WithBinaryFormat
+			val, err := r.decodeColumnData(col.Data, column.DataType, pgtype.BinaryFormatCode)
 			if err != nil {
-				return nil, fmt.Errorf("decodeTextColumnData[%s]: %w", column.Name, err)
+				return nil, fmt.Errorf("decodeColumnData[%s]: %w", column.Name, err)
 			}
 			rawMessage[column.Name] = val
+		default:
+			return nil, fmt.Errorf("%w: column[%s] format[%c]", ErrColumnFormatInvalid, column.Name, col.DataType)
 		}
 	}
 
@@ -171,14 +628,27 @@ func (r *Reader) getRelationColumn(relationID uint32, idx int) (*pglogrepl.Relat
 	return column, nil
 }
 
-func (r *Reader) decodeTextColumnData(data []byte, dataType uint32) (interface{}, error) {
+func (r *Reader) decodeColumnData(data []byte, dataType uint32, format int16) (interface{}, error) {
 	// If the data type is JSONB, return it as []byte
 	if dataType == pgtype.JSONBOID {
-		return data, nil
+		if format == pgtype.TextFormatCode {
+			return data, nil
+		}
+
+		// binary JSONB is the text prefixed by the format version
+		if len(data) == 0 || data[0] != jsonbBinaryVersion {
+			return nil, fmt.Errorf("%w: unsupported jsonb binary version", ErrColumnFormatInvalid)
+		}
+
+		return data[1:], nil
 	}
 
 	if dt, ok := r.typeMap.TypeForOID(dataType); ok {
-		return dt.Codec.DecodeValue(r.typeMap, dataType, pgtype.TextFormatCode, data)
+		return dt.Codec.DecodeValue(r.typeMap, dataType, format, data)
+	}
+
+	if format == pgtype.BinaryFormatCode {
+		return data, nil
 	}
 
 	return string(data), nil
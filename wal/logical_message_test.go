@@ -0,0 +1,123 @@
+package wal_test
+
+import (
+	"time"
+
+	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/internal/fakes"
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/nikolayk812/pgx-outbox/wal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *ReaderTestSuite) TestReader_LogicalMessages() {
+	t := suite.T()
+
+	const prefix = "outbox"
+
+	writer, err := outbox.NewLogicalMessageWriter(prefix)
+	require.NoError(t, err)
+
+	reader, err := wal.NewReader(suite.readerConnStr, "", "publication_logical", "slot_logical",
+		wal.WithLogicalMessages(prefix))
+	require.NoError(t, err)
+	defer reader.Close()
+
+	msgCh, _, err := reader.Start(ctx)
+	require.NoError(t, err)
+
+	msg1 := fakes.FakeMessage()
+	msg2 := fakes.FakeMessage()
+	msg3 := fakes.FakeMessage()
+	msg3.Metadata = map[string]string{"key": "value"}
+
+	// GIVEN messages emitted inside transactions
+	tx, err := suite.pool.Begin(ctx)
+	require.NoError(t, err)
+	id, err := writer.Write(ctx, tx, msg1)
+	require.NoError(t, err)
+	assert.Positive(t, id)
+	require.NoError(t, tx.Commit(ctx))
+
+	// AND messages of a rolled back transaction, other prefix or non-transactional
+	tx, err = suite.pool.Begin(ctx)
+	require.NoError(t, err)
+	_, err = writer.Write(ctx, tx, fakes.FakeMessage())
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback(ctx))
+
+	_, err = suite.pool.Exec(ctx, "SELECT pg_logical_emit_message(true, 'other', 'content')")
+	require.NoError(t, err)
+	_, err = suite.pool.Exec(ctx, "SELECT pg_logical_emit_message(false, $1, 'content')", prefix)
+	require.NoError(t, err)
+
+	ids, err := writer.WriteBatch(ctx, suite.pool, []types.Message{msg2, msg3})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	assert.Less(t, ids[0], ids[1])
+
+	// WHEN
+	actual := []types.Message{suite.receive(msgCh), suite.receive(msgCh), suite.receive(msgCh)}
+
+	// THEN only messages of committed transactions with the prefix are received
+	assertEqualMessages(t, []types.Message{msg1, msg2, msg3}, actual)
+	assert.Equal(t, []int64{id, ids[0], ids[1]}, types.Messages(actual).IDs())
+	assert.False(t, actual[0].CreatedAt.IsZero())
+
+	select {
+	case message := <-msgCh:
+		t.Fatalf("unexpected message: %v", message)
+	case <-time.After(time.Second):
+	}
+}
+
+func (suite *ReaderTestSuite) TestLogicalMessageWriter_Write() {
+	writer, err := outbox.NewLogicalMessageWriter("outbox")
+	suite.noError(err)
+
+	delayed := fakes.FakeMessage()
+	delayed.DeliverAt = time.Now().Add(time.Hour)
+
+	idempotent := fakes.FakeMessage()
+	idempotent.IdempotencyKey = "key"
+
+	invalid := fakes.FakeMessage()
+	invalid.Topic = ""
+
+	tests := []struct {
+		name    string
+		message types.Message
+		wantErr error
+	}{
+		{
+			name:    "delayed",
+			message: delayed,
+			wantErr: outbox.ErrLogicalMessageFieldUnsupported,
+		},
+		{
+			name:    "idempotent",
+			message: idempotent,
+			wantErr: outbox.ErrLogicalMessageFieldUnsupported,
+		},
+		{
+			name:    "invalid",
+			message: invalid,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			t := suite.T()
+
+			_, err := writer.Write(ctx, suite.pool, tt.message)
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	_, err = outbox.NewLogicalMessageWriter("")
+	suite.Require().ErrorIs(err, outbox.ErrPrefixEmpty)
+}
@@ -3,16 +3,97 @@ package wal
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pglogrepl"
 	"github.com/nikolayk812/pgx-outbox/types"
 )
 
 type RawMessage map[string]interface{}
 
-//nolint:nonamedreturns
+// Operation is the kind of the table change, see WithOperations.
+type Operation string
+
+const (
+	OperationInsert   Operation = "insert"
+	OperationUpdate   Operation = "update"
+	OperationDelete   Operation = "delete"
+	OperationTruncate Operation = "truncate"
+)
+
+type unchangedToast struct{}
+
+// UnchangedToast is the RawMessage value of a TOASTed column unchanged by UPDATE, as Postgres does not send it.
+var UnchangedToast = unchangedToast{} //nolint:gochecknoglobals
+
+// Message is a RawMessage emitted by Reader with its position in WAL.
+type Message struct {
+	// RawMessage is the new row of insert and update, it is nil for delete and truncate.
+	RawMessage
+
+	// Operation is OperationInsert for inserted rows and logical decoding messages.
+	Operation Operation
+
+	// Schema and Table of the changed row, empty for logical decoding messages.
+	Schema string
+	Table  string
+
+	// Old is the old row of update and delete: key columns only by default REPLICA IDENTITY,
+	// all columns by REPLICA IDENTITY FULL, nil for update if the key is not changed.
+	Old RawMessage
+
+	// LSN is the position which is safe to acknowledge by Reader.Ack once the message is published:
+	// it is the end of the message transaction for the last message of the transaction,
+	// and the end of the previous transaction for the other messages of the transaction.
+	// LSNs of emitted messages never decrease.
+	LSN pglogrepl.LSN
+
+	// Xid, CommitLSN and CommitTime identify the transaction which inserted the message.
+	Xid        uint32
+	CommitLSN  pglogrepl.LSN
+	CommitTime time.Time
+
+	// Last is true for the last message of the transaction.
+	Last bool
+}
+
+// Transaction is a batch of messages committed together, emitted by Reader.StartTransactions.
+type Transaction struct {
+	Xid        uint32
+	CommitLSN  pglogrepl.LSN
+	CommitTime time.Time
+
+	// LSN is the position which is safe to acknowledge by Reader.Ack once all messages are published.
+	LSN pglogrepl.LSN
+
+	Messages []Message
+}
+
+// ToOutboxMessages converts messages of the transaction, it fails for delete and truncate operations.
+func (tx Transaction) ToOutboxMessages() (types.Messages, error) {
+	messages := make(types.Messages, 0, len(tx.Messages))
+
+	for idx, message := range tx.Messages {
+		msg, err := message.ToOutboxMessage()
+		if err != nil {
+			return nil, fmt.Errorf("ToOutboxMessage index[%d]: %w", idx, err)
+		}
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
+
+//nolint:nonamedreturns,cyclop,funlen
 func (raw RawMessage) ToOutboxMessage() (m types.Message, _ error) {
 	msg := types.Message{}
 
+	for name, value := range raw {
+		if value == UnchangedToast {
+			return m, fmt.Errorf("invalid field[%s]: %w", name, ErrColumnUnchangedToast)
+		}
+	}
+
 	rawID := raw["id"]
 	if id, ok := rawID.(int64); ok {
 		msg.ID = id
@@ -52,5 +133,117 @@ func (raw RawMessage) ToOutboxMessage() (m types.Message, _ error) {
 		return m, fmt.Errorf("invalid field[payload]: expected []byte, got %T", rawPayload)
 	}
 
+	rawContentType := raw["content_type"]
+	if rawContentType != nil {
+		if contentType, ok := rawContentType.(string); ok {
+			msg.ContentType = contentType
+		} else {
+			return m, fmt.Errorf("invalid field[content_type]: expected string, got %T", rawContentType)
+		}
+	}
+
+	rawUUID := raw["uuid"]
+	if rawUUID != nil {
+		if id, ok := rawUUID.([16]byte); ok {
+			msg.UUID = id
+		} else {
+			return m, fmt.Errorf("invalid field[uuid]: expected [16]byte, got %T", rawUUID)
+		}
+	}
+
+	rawDeliverAt := raw["deliver_at"]
+	if rawDeliverAt != nil {
+		if deliverAt, ok := rawDeliverAt.(time.Time); ok {
+			msg.DeliverAt = deliverAt
+		} else {
+			return m, fmt.Errorf("invalid field[deliver_at]: expected time.Time, got %T", rawDeliverAt)
+		}
+	}
+
+	rawPriority := raw["priority"]
+	if rawPriority != nil {
+		if priority, ok := rawPriority.(int16); ok {
+			msg.Priority = priority
+		} else {
+			return m, fmt.Errorf("invalid field[priority]: expected int16, got %T", rawPriority)
+		}
+	}
+
+	rawIdempotencyKey := raw["idempotency_key"]
+	if rawIdempotencyKey != nil {
+		if key, ok := rawIdempotencyKey.(string); ok {
+			msg.IdempotencyKey = key
+		} else {
+			return m, fmt.Errorf("invalid field[idempotency_key]: expected string, got %T", rawIdempotencyKey)
+		}
+	}
+
+	rawCreatedAt := raw["created_at"]
+	if rawCreatedAt != nil {
+		if createdAt, ok := rawCreatedAt.(time.Time); ok {
+			msg.CreatedAt = createdAt
+		} else {
+			return m, fmt.Errorf("invalid field[created_at]: expected time.Time, got %T", rawCreatedAt)
+		}
+	}
+
+	rawPublishedAt := raw["published_at"]
+	if rawPublishedAt != nil {
+		if publishedAt, ok := rawPublishedAt.(time.Time); ok {
+			msg.PublishedAt = publishedAt
+		} else {
+			return m, fmt.Errorf("invalid field[published_at]: expected time.Time, got %T", rawPublishedAt)
+		}
+	}
+
+	// payloads are decompressed the same way as by outbox.Reader, up to types.MaxDecompressedSize
+	msg, err := msg.Decompressed()
+	if err != nil {
+		return m, fmt.Errorf("msg.Decompressed: %w", err)
+	}
+
 	return msg, nil
 }
+
+// logicalRawMessage converts the logical decoding message emitted by outbox.NewLogicalMessageWriter
+// to RawMessage with the same fields as an inserted row of the outbox table, its LSN is the ID.
+func logicalRawMessage(msg *pglogrepl.LogicalDecodingMessageV2) (RawMessage, error) {
+	message, err := types.UnmarshalLogicalMessage(msg.Content)
+	if err != nil {
+		return nil, fmt.Errorf("types.UnmarshalLogicalMessage: %w", err)
+	}
+
+	raw := RawMessage{
+		"id":         int64(msg.LSN), //nolint:gosec
+		"uuid":       [16]byte(message.UUID),
+		"broker":     message.Broker,
+		"topic":      message.Topic,
+		"payload":    message.Payload,
+		"priority":   message.Priority,
+		"created_at": message.CreatedAt,
+	}
+
+	if len(message.Metadata) > 0 {
+		metadata, err := json.Marshal(message.Metadata)
+		if err != nil {
+			return nil, fmt.Errorf("json.Marshal[metadata]: %w", err)
+		}
+		raw["metadata"] = metadata
+	}
+
+	if message.ContentType != "" {
+		raw["content_type"] = message.ContentType
+	}
+
+	return raw, nil
+}
+
+// delayed reports whether the message has deliver_at column set to a time after now.
+func (raw RawMessage) delayed(now time.Time) bool {
+	deliverAt, ok := raw["deliver_at"].(time.Time)
+	if !ok {
+		return false
+	}
+
+	return deliverAt.After(now)
+}
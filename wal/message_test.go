@@ -2,7 +2,10 @@ package wal_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/nikolayk812/pgx-outbox/types"
 	"github.com/nikolayk812/pgx-outbox/wal"
 	"github.com/stretchr/testify/require"
 )
@@ -55,11 +58,51 @@ func TestToOutboxMessage(t *testing.T) {
 			raw:     wal.RawMessage{"id": int64(1), "broker": "kafka", "topic": "topic", "payload": "not-a-byte-slice"},
 			wantErr: "invalid field[payload]: expected []byte, got string",
 		},
+		{
+			name: "invalid deliver_at type",
+			raw: wal.RawMessage{
+				"id": int64(1), "broker": "kafka", "topic": "topic", "payload": []byte("{}"), "deliver_at": "tomorrow",
+			},
+			wantErr: "invalid field[deliver_at]: expected time.Time, got string",
+		},
+		{
+			name: "invalid uuid type",
+			raw: wal.RawMessage{
+				"id": int64(1), "broker": "kafka", "topic": "topic", "payload": []byte("{}"), "uuid": "not-a-uuid",
+			},
+			wantErr: "invalid field[uuid]: expected [16]byte, got string",
+		},
+		{
+			name: "invalid content_type type",
+			raw: wal.RawMessage{
+				"id": int64(1), "broker": "kafka", "topic": "topic", "payload": []byte("{}"), "content_type": []byte("json"),
+			},
+			wantErr: "invalid field[content_type]: expected string, got []uint8",
+		},
+		{
+			name: "invalid priority type",
+			raw: wal.RawMessage{
+				"id": int64(1), "broker": "kafka", "topic": "topic", "payload": []byte("{}"), "priority": 1,
+			},
+			wantErr: "invalid field[priority]: expected int16, got int",
+		},
+		{
+			name: "invalid created_at type",
+			raw: wal.RawMessage{
+				"id": int64(1), "broker": "kafka", "topic": "topic", "payload": []byte("{}"), "created_at": "now",
+			},
+			wantErr: "invalid field[created_at]: expected time.Time, got string",
+		},
 		{
 			name:    "invalid metadata JSON",
 			raw:     wal.RawMessage{"id": int64(1), "broker": "kafka", "topic": "topic", "metadata": []byte("invalid-json")},
 			wantErr: "json.Unmarshal[metadata]:",
 		},
+		{
+			name:    "unchanged TOAST payload",
+			raw:     wal.RawMessage{"id": int64(1), "broker": "kafka", "topic": "topic", "payload": wal.UnchangedToast},
+			wantErr: "invalid field[payload]: column is unchanged TOAST value",
+		},
 	}
 
 	for _, tt := range tests {
@@ -72,3 +115,70 @@ func TestToOutboxMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestToOutboxMessage_Valid(t *testing.T) {
+	t.Parallel()
+
+	id := uuid.Must(uuid.NewV7())
+	createdAt := time.Now().UTC()
+
+	raw := wal.RawMessage{
+		"id":              int64(1),
+		"uuid":            [16]byte(id),
+		"broker":          "kafka",
+		"topic":           "topic",
+		"metadata":        []byte(`{"key":"value"}`),
+		"payload":         []byte(`{"content":"test"}`),
+		"content_type":    types.ContentTypeJSON,
+		"deliver_at":      nil,
+		"priority":        int16(5),
+		"idempotency_key": "key",
+		"created_at":      createdAt,
+		"published_at":    nil,
+	}
+
+	actual, err := raw.ToOutboxMessage()
+	require.NoError(t, err)
+
+	expected := types.Message{
+		ID:             1,
+		UUID:           id,
+		Broker:         "kafka",
+		Topic:          "topic",
+		Metadata:       map[string]string{"key": "value"},
+		Payload:        []byte(`{"content":"test"}`),
+		ContentType:    types.ContentTypeJSON,
+		Priority:       5,
+		IdempotencyKey: "key",
+		CreatedAt:      createdAt,
+	}
+	require.Equal(t, expected, actual)
+}
+
+func TestToOutboxMessage_Compressed(t *testing.T) {
+	t.Parallel()
+
+	payload := []byte(`{"content":"test"}`)
+
+	compressed, err := types.Compress(payload, types.ContentEncodingZstd)
+	require.NoError(t, err)
+
+	raw := wal.RawMessage{
+		"id":       int64(1),
+		"broker":   "kafka",
+		"topic":    "topic",
+		"metadata": []byte(`{"content_encoding":"zstd"}`),
+		"payload":  compressed,
+	}
+
+	actual, err := raw.ToOutboxMessage()
+	require.NoError(t, err)
+
+	expected := types.Message{
+		ID:      1,
+		Broker:  "kafka",
+		Topic:   "topic",
+		Payload: payload,
+	}
+	require.Equal(t, expected, actual)
+}
@@ -0,0 +1,119 @@
+package wal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+const defaultMonitorInterval = time.Minute
+
+// AlertFunc is called by Monitor for a slot retaining more WAL than the threshold.
+// dropped is true if the slot is dropped by WithDropExceeded option.
+type AlertFunc func(ctx context.Context, slot Slot, dropped bool)
+
+// Monitor watches WAL retained by replication slots, so an abandoned slot does not fill the disk.
+type Monitor struct {
+	admin        *Admin
+	maxRetained  int64
+	interval     time.Duration
+	slots        []string
+	dropExceeded bool
+	alert        AlertFunc
+}
+
+// NewMonitor alerts on slots retaining more than maxRetainedBytes of WAL, by slog.Warn by default.
+func NewMonitor(admin *Admin, maxRetainedBytes int64, opts ...MonitorOption) (*Monitor, error) {
+	if admin == nil {
+		return nil, ErrAdminNil
+	}
+	if maxRetainedBytes <= 0 {
+		return nil, fmt.Errorf("%w: %d", ErrRetainedThresholdInvalid, maxRetainedBytes)
+	}
+
+	m := &Monitor{
+		admin:       admin,
+		maxRetained: maxRetainedBytes,
+		interval:    defaultMonitorInterval,
+		alert:       logAlert,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if m.alert == nil {
+		m.alert = logAlert
+	}
+
+	if m.interval <= 0 {
+		return nil, fmt.Errorf("%w: %s", ErrMonitorIntervalInvalid, m.interval)
+	}
+
+	// slots of other consumers, i.e. Debezium or subscriptions, must not be dropped
+	if m.dropExceeded && len(m.slots) == 0 {
+		return nil, ErrMonitorSlotsRequired
+	}
+
+	return m, nil
+}
+
+// Run checks slots every interval until ctx is done, it returns on the first failed check.
+func (m *Monitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Check(ctx); err != nil {
+			return fmt.Errorf("Check: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check alerts on slots exceeding the threshold and drops the inactive ones with WithDropExceeded option.
+// Active slots are never dropped, as they are in use. It returns the exceeding slots.
+func (m *Monitor) Check(ctx context.Context) ([]Slot, error) {
+	slots, err := m.admin.Slots(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("admin.Slots: %w", err)
+	}
+
+	var exceeded []Slot
+
+	for _, slot := range slots {
+		if len(m.slots) > 0 && !slices.Contains(m.slots, slot.Name) {
+			continue
+		}
+		if slot.RetainedBytes <= m.maxRetained {
+			continue
+		}
+
+		exceeded = append(exceeded, slot)
+
+		dropped := false
+		if m.dropExceeded && !slot.Active {
+			if err := m.admin.DropSlot(ctx, slot.Name); err != nil {
+				return exceeded, fmt.Errorf("admin.DropSlot[%s]: %w", slot.Name, err)
+			}
+			dropped = true
+		}
+
+		m.alert(ctx, slot, dropped)
+	}
+
+	return exceeded, nil
+}
+
+func logAlert(_ context.Context, slot Slot, dropped bool) {
+	slog.Warn("wal.Monitor replication slot retains too much WAL",
+		"slot", slot.Name, "active", slot.Active, "retained_bytes", slot.RetainedBytes,
+		"lag_bytes", slot.LagBytes, "dropped", dropped)
+}
@@ -0,0 +1,74 @@
+package wal_test
+
+import (
+	"time"
+
+	"github.com/nikolayk812/pgx-outbox/wal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *ReaderTestSuite) TestReader_Operations() {
+	t := suite.T()
+
+	const table = "cdc_items"
+
+	_, err := suite.pool.Exec(ctx, `CREATE TABLE `+table+` (id BIGINT PRIMARY KEY, name TEXT NOT NULL, note TEXT)`)
+	require.NoError(t, err)
+	defer func() {
+		_, err := suite.pool.Exec(ctx, `DROP TABLE `+table)
+		suite.noError(err)
+	}()
+
+	_, err = suite.pool.Exec(ctx, `ALTER TABLE `+table+` REPLICA IDENTITY FULL`)
+	require.NoError(t, err)
+
+	reader, err := wal.NewReader(suite.readerConnStr, table, "publication_operations", "slot_operations",
+		wal.WithOperations(wal.OperationUpdate, wal.OperationDelete, wal.OperationTruncate))
+	require.NoError(t, err)
+	defer reader.Close()
+
+	msgCh, _, err := reader.Start(ctx)
+	require.NoError(t, err)
+
+	// GIVEN
+	for _, query := range []string{
+		`INSERT INTO ` + table + ` (id, name, note) VALUES (1, 'first', 'note')`, // inserts are not emitted
+		`UPDATE ` + table + ` SET name = 'second' WHERE id = 1`,
+		`DELETE FROM ` + table + ` WHERE id = 1`,
+		`TRUNCATE ` + table,
+	} {
+		_, err := suite.pool.Exec(ctx, query)
+		require.NoError(t, err)
+	}
+
+	// WHEN
+	update := receiveMessage(t, msgCh)
+	deleted := receiveMessage(t, msgCh)
+	truncate := receiveMessage(t, msgCh)
+
+	// THEN
+	assert.Equal(t, wal.OperationUpdate, update.Operation)
+	assert.Equal(t, wal.RawMessage{"id": int64(1), "name": "second", "note": "note"}, update.RawMessage)
+	assert.Equal(t, wal.RawMessage{"id": int64(1), "name": "first", "note": "note"}, update.Old)
+
+	assert.Equal(t, wal.OperationDelete, deleted.Operation)
+	assert.Nil(t, deleted.RawMessage)
+	assert.Equal(t, wal.RawMessage{"id": int64(1), "name": "second", "note": "note"}, deleted.Old)
+
+	assert.Equal(t, wal.OperationTruncate, truncate.Operation)
+	assert.Nil(t, truncate.RawMessage)
+	assert.True(t, truncate.Last)
+}
+
+func receiveMessage(t require.TestingT, msgCh <-chan wal.Message) wal.Message {
+	select {
+	case message, ok := <-msgCh:
+		require.True(t, ok, "message channel is closed")
+		return message
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "timeout waiting for message")
+	}
+
+	return wal.Message{}
+}
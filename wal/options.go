@@ -1,6 +1,11 @@
 package wal
 
-import "time"
+import (
+	"time"
+
+	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/types"
+)
 
 type ReadOption func(*Reader)
 
@@ -21,3 +26,184 @@ func WithChannelBuffer(buffer int) ReadOption {
 		r.messageBuffer = buffer
 	}
 }
+
+// WithReconnect enables reconnecting on connection errors with exponential backoff from initialBackoff up to maxBackoff,
+// zero or negative maxAttempts means unlimited attempts.
+// Other errors, i.e. decoding or ErrChannelFull of BackpressureError, stop the reader as without reconnecting.
+// Streaming resumes from the last processed LSN, messages of a transaction interrupted by the disconnect
+// are received again, so consumers should be idempotent.
+// WithPermanentSlot option is required, as a temporary slot is dropped on disconnect.
+func WithReconnect(initialBackoff, maxBackoff time.Duration, maxAttempts int) ReadOption {
+	return func(r *Reader) {
+		r.reconnect = true
+		r.reconnectBackoff = initialBackoff
+		r.reconnectMaxBackoff = maxBackoff
+		r.reconnectMaxAttempts = maxAttempts
+	}
+}
+
+// WithManualAck disables confirming messages to Postgres once they are sent to the channel,
+// so the replication slot retains WAL until messages are confirmed by Reader.Ack after publishing,
+// unconfirmed messages are received again after restart of the reader with WithPermanentSlot option.
+func WithManualAck() ReadOption {
+	return func(r *Reader) {
+		r.manualAck = true
+	}
+}
+
+// BackpressurePolicy defines what Reader does when the message channel is full, as the consumer is slow.
+type BackpressurePolicy int
+
+const (
+	// BackpressureBlock waits for the consumer, standby status updates are still sent to Postgres meanwhile.
+	BackpressureBlock BackpressurePolicy = iota
+	// BackpressureError stops the reader with ErrChannelFull.
+	BackpressureError
+	// BackpressureDrop skips the message, it stays unpublished in the outbox table to be published by outbox.Forwarder.
+	BackpressureDrop
+)
+
+// WithBackpressure sets the policy for the full message channel, the default is BackpressureBlock.
+// BackpressureDrop is rejected together with WithLogicalMessages option, as logical messages are not stored in a table.
+func WithBackpressure(policy BackpressurePolicy) ReadOption {
+	return func(r *Reader) {
+		r.backpressure = policy
+	}
+}
+
+// WithProtocolVersion sets pgoutput protocol version from 2 to 4, the default is 2.
+// Version 3 requires Postgres 15 and enables WithTwoPhase option, version 4 requires Postgres 16.
+func WithProtocolVersion(version int) ReadOption {
+	return func(r *Reader) {
+		r.protocolVersion = version
+	}
+}
+
+// WithStreaming enables streaming of large in-progress transactions, so Postgres sends their changes
+// once they exceed logical_decoding_work_mem instead of decoding the whole transaction before sending.
+// Messages of streamed transactions are buffered by Reader and emitted once the transaction is committed,
+// messages of aborted transactions and subtransactions are discarded.
+// Buffered messages beyond spillThreshold are spilled to a temporary file,
+// zero or negative spillThreshold keeps them in memory.
+func WithStreaming(spillThreshold int) ReadOption {
+	return func(r *Reader) {
+		r.streaming = true
+		r.spillThreshold = spillThreshold
+	}
+}
+
+// WithTwoPhase enables decoding of transactions prepared for two-phase commit at PREPARE TRANSACTION,
+// their messages are buffered by Reader and emitted at COMMIT PREPARED or discarded at ROLLBACK PREPARED.
+// The confirmed LSN does not advance beyond pending prepared transactions, so they are received again after restart.
+// Requires protocol version 3 or higher, see WithProtocolVersion.
+func WithTwoPhase() ReadOption {
+	return func(r *Reader) {
+		r.twoPhase = true
+	}
+}
+
+// WithTables adds tables to the publication besides the table of NewReader, so one replication slot serves them all.
+// Messages are tagged with Schema and Table, see Router to handle them per table.
+func WithTables(tables ...string) ReadOption {
+	return func(r *Reader) {
+		r.tables = append(r.tables, tables...)
+	}
+}
+
+// WithFilter limits messages by brokers and topics, like outbox.WithReadFilter does.
+// Postgres 15+ filters rows by the publication created by Reader, older servers send all rows,
+// so Reader filters them client-side. Filtering of updates and deletes in the publication requires
+// REPLICA IDENTITY FULL, otherwise Postgres fails the updates and deletes of the table.
+func WithFilter(filter types.MessageFilter) ReadOption {
+	return func(r *Reader) {
+		r.filter = filter
+	}
+}
+
+// WithColumns limits columns of messages, i.e. to skip large columns not used by the consumer.
+// Postgres 15+ sends only these columns by the publication created by Reader, older servers send all columns,
+// so Reader drops the others client-side. Columns required by RawMessage.ToOutboxMessage must be kept to use it,
+// as well as REPLICA IDENTITY columns for updates and deletes.
+func WithColumns(columns ...string) ReadOption {
+	return func(r *Reader) {
+		r.columns = columns
+	}
+}
+
+// WithOperations sets the table changes published and emitted by Reader, the default is OperationInsert only.
+// It is for change data capture of the tables, Forwarder supports OperationInsert only.
+// An existing publication is not altered, so it should be dropped to change the operations.
+func WithOperations(operations ...Operation) ReadOption {
+	return func(r *Reader) {
+		r.operations = operations
+	}
+}
+
+// WithBinaryFormat requests column values in binary format instead of text format, which is cheaper to decode
+// for bytea payloads and timestamps. Requires Postgres 14 and all column types supporting binary send and receive.
+func WithBinaryFormat() ReadOption {
+	return func(r *Reader) {
+		r.binary = true
+	}
+}
+
+// WithLogicalMessages enables reading messages emitted by outbox.NewLogicalMessageWriter with the prefix,
+// IDs of such messages are their LSNs. Empty table is allowed then, so the reader reads logical messages only,
+// NewForwarder rejects WithMarkPublished option for such readers, as there are no rows to mark.
+func WithLogicalMessages(prefix string) ReadOption {
+	return func(r *Reader) {
+		r.logicalPrefix = prefix
+	}
+}
+
+type ForwardOption func(*Forwarder)
+
+// WithMarkPublished enables marking forwarded messages as published in the outbox table by marker.Ack,
+// i.e. outbox.NewReader on the same table, so outbox.Forwarder does not publish them again.
+// It is not supported for readers watching several tables by WithTables option or with WithLogicalMessages option.
+func WithMarkPublished(marker outbox.Reader) ForwardOption {
+	return func(f *Forwarder) {
+		f.marker = marker
+	}
+}
+
+type RouteOption func(*Router)
+
+// WithDefaultHandler sets the handler of messages of tables without handlers, i.e. logical decoding messages.
+func WithDefaultHandler(handler Handler) RouteOption {
+	return func(rt *Router) {
+		rt.fallback = handler
+	}
+}
+
+type MonitorOption func(*Monitor)
+
+// WithMonitorInterval sets the interval of Monitor.Run checks, the default is 1 minute.
+func WithMonitorInterval(interval time.Duration) MonitorOption {
+	return func(m *Monitor) {
+		m.interval = interval
+	}
+}
+
+// WithMonitorSlots limits checks to the slots, all logical slots of the current database are checked by default.
+func WithMonitorSlots(slots ...string) MonitorOption {
+	return func(m *Monitor) {
+		m.slots = slots
+	}
+}
+
+// WithDropExceeded drops inactive slots exceeding the threshold, so Postgres removes the retained WAL.
+// Messages not consumed by the slot are lost for WAL readers then, outbox.Forwarder still publishes them.
+// WithMonitorSlots option listing slots of wal.Reader is required, so slots of other consumers are never dropped.
+func WithDropExceeded() MonitorOption {
+	return func(m *Monitor) {
+		m.dropExceeded = true
+	}
+}
+
+// WithAlert sets the function called for slots exceeding the threshold, slog.Warn is used by default.
+func WithAlert(alert AlertFunc) MonitorOption {
+	return func(m *Monitor) {
+		m.alert = alert
+	}
+}
@@ -4,12 +4,44 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
 func (r *Reader) createPublication(ctx context.Context) error {
-	query := fmt.Sprintf("CREATE PUBLICATION %s FOR TABLE %s WITH (publish = 'insert')", r.publication, r.table)
+	// publish_via_partition_root makes inserts into partitions of a partitioned outbox table
+	// to be published as inserts into the parent table, it is no-op for regular tables
+	tables := r.tables
+
+	if r.filtered() {
+		version, err := r.serverVersion(ctx)
+		if err != nil {
+			return fmt.Errorf("serverVersion: %w", err)
+		}
+
+		if version >= publicationFilterServerVersion {
+			tables = make([]string, 0, len(r.tables))
+			for _, table := range r.tables {
+				tables = append(tables, r.tableSpec(table))
+			}
+		} else {
+			slog.Warn("wal.Reader filters messages client-side, Postgres 15 is required to filter in publication",
+				"publication", r.publication, "server_version_num", version)
+		}
+	}
+
+	query := fmt.Sprintf("CREATE PUBLICATION %s FOR TABLE %s WITH (publish = '%s', publish_via_partition_root = true)",
+		r.publication, strings.Join(tables, ", "), r.publish())
+
+	// logical decoding messages are not published by tables, but pgoutput requires a publication
+	if len(r.tables) == 0 {
+		query = fmt.Sprintf("CREATE PUBLICATION %s", r.publication)
+	}
+
+	// marks the publication to be dropped by Admin, both statements run in the same implicit transaction
+	query += fmt.Sprintf("; COMMENT ON PUBLICATION %s IS '%s'", r.publication, publicationComment)
 
 	result := r.getConn().Exec(ctx, query)
 	defer closeResource("create_publication_query_result", result)
@@ -46,3 +78,18 @@ func (r *Reader) publicationExists(ctx context.Context) (bool, error) {
 
 	return true, nil
 }
+
+// publish returns the publish parameter of the publication, i.e. "insert, update".
+func (r *Reader) publish() string {
+	operations := make([]string, 0, len(r.operations))
+	for _, operation := range r.operations {
+		operations = append(operations, string(operation))
+	}
+
+	return strings.Join(operations, ", ")
+}
+
+// filtered reports whether the publication has row filters or column lists.
+func (r *Reader) filtered() bool {
+	return len(r.filter.Brokers) > 0 || len(r.filter.Topics) > 0 || len(r.columns) > 0
+}
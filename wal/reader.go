@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -14,6 +18,7 @@ import (
 	"github.com/jackc/pgx/v5/pgproto3"
 	"github.com/jackc/pgx/v5/pgtype"
 	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/types"
 )
 
 const (
@@ -22,6 +27,13 @@ const (
 	defaultStandbyTimeout = time.Second * 10
 	defaultChannelBuffer  = 1_000
 
+	defaultProtocolVersion  = 2
+	minProtocolVersion      = 2
+	maxProtocolVersion      = 4
+	twoPhaseProtocolVersion = 3
+
+	jsonbBinaryVersion = 1
+
 	ConnectionStrReplicationDatabaseParam = "replication=database"
 )
 
@@ -31,11 +43,7 @@ const (
 //
 // Limitations:
 // - pgoutput built-in Postgres plugin only; wal2json, decoderbufs are not supported
-// - single table per publication / replication slot
-// - logical replication protocol v2 only; v3 and v4 are not supported
-// - only insert operations are supported
-// - pg_logical_emit_message() is not supported
-// - transaction streaming is not supported
+// - messages with deliver_at in the future are skipped, outbox.Forwarder should publish them when due
 // - custom types are not supported
 
 type Reader struct {
@@ -43,7 +51,7 @@ type Reader struct {
 	conn     *pgconn.PgConn
 	connLock sync.Mutex // as pgconn.PgConn is not concurrency-safe
 
-	table         string
+	tables        []string // the table of NewReader and WithTables
 	publication   string
 	slot          string
 	permanentSlot bool
@@ -52,17 +60,47 @@ type Reader struct {
 	onceClose sync.Once
 	closeCh   chan struct{}
 
+	reconnect            bool
+	reconnectBackoff     time.Duration
+	reconnectMaxBackoff  time.Duration
+	reconnectMaxAttempts int
+
 	standbyTimeout      time.Duration
 	nextStandbyDeadline time.Time
 
 	lastReceivedLSN  pglogrepl.LSN
 	lastProcessedLSN atomic.Uint64
+	inTransaction    bool // LSN is advanced on commit only, so an interrupted transaction is received again
+
+	manualAck      bool
+	lastCommitLSN  pglogrepl.LSN
+	lastEmittedLSN atomic.Uint64
+	held           *Message // the last message of a transaction is known on the next insert or commit only
+	tx             txInfo
+
+	protocolVersion int
+	streaming       bool
+	spillThreshold  int
+	twoPhase        bool
+	logicalPrefix   string
+	binary          bool
+	operations      []Operation
+	filter          types.MessageFilter
+	columns         []string
+
+	inStream  bool
+	streamXid uint32
+	streams   map[uint32]*txBuffer // in-progress streamed transactions by xid
+	preparing *txBuffer            // between BeginPrepare and Prepare
+	prepared  map[string]preparedTx
+	holdLSN   atomic.Uint64 // zero if there are no prepared transactions
 
 	relations map[uint32]*pglogrepl.RelationMessageV2 // to maintain tables schemas as they are sent once
 	typeMap   *pgtype.Map
 
 	messageBuffer int
-	messageCh     chan RawMessage
+	backpressure  BackpressurePolicy
+	messageCh     chan Message
 	errorCh       chan error
 }
 
@@ -71,9 +109,6 @@ func NewReader(connStr, table, publication, slot string, opts ...ReadOption) (*R
 		// pglogrepl.IdentifySystem() call requires replication=database parameter
 		return nil, ErrConnectionStrReplicationDatabaseParamAbsent
 	}
-	if table == "" {
-		return nil, outbox.ErrTableEmpty
-	}
 	if publication == "" {
 		return nil, ErrPublicationEmpty
 	}
@@ -83,7 +118,6 @@ func NewReader(connStr, table, publication, slot string, opts ...ReadOption) (*R
 
 	r := &Reader{
 		connStr:             connStr,
-		table:               table,
 		publication:         publication,
 		slot:                slot,
 		standbyTimeout:      defaultStandbyTimeout,
@@ -91,20 +125,73 @@ func NewReader(connStr, table, publication, slot string, opts ...ReadOption) (*R
 		relations:           map[uint32]*pglogrepl.RelationMessageV2{},
 		typeMap:             pgtype.NewMap(),
 		messageBuffer:       defaultChannelBuffer,
+		protocolVersion:     defaultProtocolVersion,
+		operations:          []Operation{OperationInsert},
+		streams:             map[uint32]*txBuffer{},
+		prepared:            map[string]preparedTx{},
 		closeCh:             make(chan struct{}),
 		errorCh:             make(chan error, 1),
 	}
 
+	if table != "" {
+		r.tables = append(r.tables, table)
+	}
+
 	for _, opt := range opts {
 		opt(r)
 	}
 
-	r.messageCh = make(chan RawMessage, r.messageBuffer)
+	if slices.Contains(r.tables, "") {
+		return nil, outbox.ErrTableEmpty
+	}
+	if len(r.tables) == 0 && r.logicalPrefix == "" {
+		return nil, outbox.ErrTableEmpty
+	}
+
+	if r.protocolVersion < minProtocolVersion || r.protocolVersion > maxProtocolVersion {
+		return nil, fmt.Errorf("%w: %d", ErrProtocolVersionInvalid, r.protocolVersion)
+	}
+	if r.twoPhase && r.protocolVersion < twoPhaseProtocolVersion {
+		return nil, fmt.Errorf("%w: two-phase requires %d, got %d",
+			ErrProtocolVersionInvalid, twoPhaseProtocolVersion, r.protocolVersion)
+	}
+
+	if err := r.filter.Validate(); err != nil {
+		return nil, fmt.Errorf("filter.Validate: %w", err)
+	}
+
+	if err := validateOperations(r.operations); err != nil {
+		return nil, fmt.Errorf("validateOperations: %w", err)
+	}
+
+	switch r.backpressure {
+	case BackpressureBlock, BackpressureError, BackpressureDrop:
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrBackpressurePolicyInvalid, r.backpressure)
+	}
+
+	// logical decoding messages are not stored in a table, so a dropped one would be lost
+	if r.backpressure == BackpressureDrop && r.logicalPrefix != "" {
+		return nil, ErrBackpressureDropUnsupported
+	}
+
+	if r.reconnect {
+		// a temporary slot is dropped with the connection, so messages written meanwhile would be lost
+		if !r.permanentSlot {
+			return nil, ErrReconnectPermanentSlotRequired
+		}
+		if r.reconnectBackoff <= 0 || r.reconnectMaxBackoff < r.reconnectBackoff {
+			return nil, fmt.Errorf("%w: initial[%s] max[%s]",
+				ErrReconnectBackoffInvalid, r.reconnectBackoff, r.reconnectMaxBackoff)
+		}
+	}
+
+	r.messageCh = make(chan Message, r.messageBuffer)
 
 	return r, nil
 }
 
-func (r *Reader) Start(ctx context.Context) (<-chan RawMessage, <-chan error, error) {
+func (r *Reader) Start(ctx context.Context) (<-chan Message, <-chan error, error) {
 	var onceErr error
 
 	r.onceStart.Do(func() {
@@ -115,9 +202,10 @@ func (r *Reader) Start(ctx context.Context) (<-chan RawMessage, <-chan error, er
 
 		go func() {
 			defer close(r.errorCh)
+			defer r.close(ctx)
 
 			// blocking call
-			if err := r.startLoop(ctx); err != nil {
+			if err := r.run(ctx); err != nil {
 				r.errorCh <- err
 				return
 			}
@@ -130,6 +218,55 @@ func (r *Reader) Start(ctx context.Context) (<-chan RawMessage, <-chan error, er
 	return r.messageCh, r.errorCh, nil
 }
 
+// StartTransactions is an alternative to Start which groups messages committed together into a Transaction,
+// i.e. to publish a whole business transaction atomically. Either Start or StartTransactions should be used.
+// Messages of a transaction interrupted by the reader closing are not emitted.
+func (r *Reader) StartTransactions(ctx context.Context) (<-chan Transaction, <-chan error, error) {
+	messageCh, errorCh, err := r.Start(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txCh := make(chan Transaction)
+
+	go func() {
+		defer close(txCh)
+
+		var tx Transaction
+
+		send := func() bool {
+			select {
+			case txCh <- tx:
+				tx = Transaction{}
+				return true
+			case <-ctx.Done():
+				return false
+			case <-r.closeCh:
+				return false
+			}
+		}
+
+		for message := range messageCh {
+			// the last message is not emitted with BackpressureDrop policy
+			if len(tx.Messages) > 0 && tx.Xid != message.Xid && !send() {
+				return
+			}
+
+			tx.Xid = message.Xid
+			tx.CommitLSN = message.CommitLSN
+			tx.CommitTime = message.CommitTime
+			tx.LSN = message.LSN
+			tx.Messages = append(tx.Messages, message)
+
+			if message.Last && !send() {
+				return
+			}
+		}
+	}()
+
+	return txCh, errorCh, nil
+}
+
 func (r *Reader) start(ctx context.Context) error {
 	if err := r.connect(ctx); err != nil {
 		return fmt.Errorf("connect: %w", err)
@@ -164,10 +301,79 @@ func (r *Reader) connect(ctx context.Context) error {
 	return nil
 }
 
+// run streams messages until the reader is closed or an error,
+// if WithReconnect option is set, connection errors are retried with backoff.
+func (r *Reader) run(ctx context.Context) error {
+	for {
+		err := r.startLoop(ctx)
+		if err == nil || !r.reconnect || ctx.Err() != nil || r.closed() || !r.isConnectionError(err) {
+			return err
+		}
+
+		if err := r.reconnectWithBackoff(ctx, err); err != nil {
+			return err
+		}
+	}
+}
+
+// reconnectWithBackoff re-establishes the replication connection and resumes streaming
+// from the last processed LSN, so messages not yet confirmed to Postgres are received again.
+func (r *Reader) reconnectWithBackoff(ctx context.Context, cause error) error {
+	backoff := r.reconnectBackoff
+
+	for attempt := 1; r.reconnectMaxAttempts <= 0 || attempt <= r.reconnectMaxAttempts; attempt++ {
+		slog.Warn("wal.Reader reconnect", "slot", r.slot, "attempt", attempt, "backoff", backoff, "error", cause)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-r.closeCh:
+			return nil
+		case <-time.After(backoff):
+		}
+
+		if cause = r.restart(ctx); cause == nil {
+			return nil
+		}
+
+		backoff = min(backoff*2, r.reconnectMaxBackoff)
+	}
+
+	return fmt.Errorf("%w: attempts[%d]: %w", ErrReconnectAttemptsExhausted, r.reconnectMaxAttempts, cause)
+}
+
+// isConnectionError reports whether err is caused by a broken connection or I/O failure,
+// other errors, i.e. decoding or ErrChannelFull, are not fixed by reconnecting and stop the reader.
+func (r *Reader) isConnectionError(err error) bool {
+	if r.getConn().IsClosed() {
+		return true
+	}
+
+	var netErr net.Error
+
+	return pgconn.Timeout(err) || pgconn.SafeToRetry(err) || errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func (r *Reader) restart(ctx context.Context) error {
+	_ = r.getConn().Close(ctx)
+
+	if err := r.connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+
+	// the slot stays active until Postgres detects the broken connection, so ErrReplicationSlotActive is retried
+	if err := r.startReplication(ctx); err != nil {
+		return fmt.Errorf("startReplication: %w", err)
+	}
+
+	r.nextStandbyDeadline = time.Now()
+
+	return nil
+}
+
 //nolint:cyclop
 func (r *Reader) startLoop(ctx context.Context) error {
-	defer r.close(ctx)
-
 	for {
 		if err := r.sendStatusUpdate(ctx); err != nil {
 			if r.getConn().IsClosed() {
@@ -216,7 +422,7 @@ func (r *Reader) startLoop(ctx context.Context) error {
 			}
 
 		case pglogrepl.XLogDataByteID:
-			if err := r.handleXLogData(msg.Data[1:]); err != nil {
+			if err := r.handleXLogData(ctx, msg.Data[1:]); err != nil {
 				return fmt.Errorf("handleXLogData: %w", err)
 			}
 		}
@@ -229,9 +435,42 @@ func (r *Reader) Close() {
 	})
 }
 
+func (r *Reader) closed() bool {
+	select {
+	case <-r.closeCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// Ack confirms to Postgres that messages up to the lsn are published, see Message.LSN.
+// The confirmed position is sent with the next standby status update,
+// so the replication slot does not retain WAL of the confirmed messages anymore.
+// Requires WithManualAck option.
+func (r *Reader) Ack(lsn pglogrepl.LSN) error {
+	if !r.manualAck {
+		return ErrManualAckDisabled
+	}
+
+	if lastEmittedLSN := pglogrepl.LSN(r.lastEmittedLSN.Load()); lsn > lastEmittedLSN {
+		return fmt.Errorf("%w: lsn[%s] last emitted[%s]", ErrAckLSNInvalid, lsn, lastEmittedLSN)
+	}
+
+	r.updateLastProcessedLSN(lsn)
+
+	return nil
+}
+
 func (r *Reader) close(ctx context.Context) {
+	// best effort to confirm the last processed LSN, as the connection might be broken already
+	r.nextStandbyDeadline = time.Now()
+	_ = r.sendStatusUpdate(ctx)
+
 	_ = r.getConn().Close(ctx)
 
+	r.resetBuffers()
+
 	close(r.messageCh)
 }
 
@@ -250,13 +489,50 @@ func (r *Reader) setConn(conn *pgconn.PgConn) {
 }
 
 func (r *Reader) updateLastProcessedLSN(lsn pglogrepl.LSN) {
+	if hold := pglogrepl.LSN(r.holdLSN.Load()); hold > 0 && lsn > hold {
+		lsn = hold
+	}
+
+	updateLSN(&r.lastProcessedLSN, lsn)
+}
+
+// txInfo identifies the transaction of emitted messages.
+type txInfo struct {
+	xid        uint32
+	commitLSN  pglogrepl.LSN
+	commitTime time.Time
+}
+
+// updateLSN advances the stored LSN, it never moves it back.
+func updateLSN(stored *atomic.Uint64, lsn pglogrepl.LSN) {
 	for {
-		current := r.lastProcessedLSN.Load()
+		current := stored.Load()
 		if lsn <= pglogrepl.LSN(current) {
 			return // Don't update
 		}
-		if r.lastProcessedLSN.CompareAndSwap(current, uint64(lsn)) {
+		if stored.CompareAndSwap(current, uint64(lsn)) {
 			return // updated, otherwise continue loop
 		}
 	}
 }
+
+func validateOperations(operations []Operation) error {
+	if len(operations) == 0 {
+		return fmt.Errorf("%w: empty", ErrOperationInvalid)
+	}
+
+	for _, operation := range operations {
+		switch operation {
+		case OperationInsert, OperationUpdate, OperationDelete, OperationTruncate:
+		default:
+			return fmt.Errorf("%w: %s", ErrOperationInvalid, operation)
+		}
+	}
+
+	return nil
+}
+
+// operationEnabled filters changes of an existing publication which publishes more operations.
+func (r *Reader) operationEnabled(operation Operation) bool {
+	return slices.Contains(r.operations, operation)
+}
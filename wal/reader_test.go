@@ -11,6 +11,7 @@ import (
 
 	toxiproxy "github.com/Shopify/toxiproxy/v2/client"
 	"github.com/google/go-cmp/cmp"
+	"github.com/jackc/pglogrepl"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -107,17 +108,39 @@ func (suite *ReaderTestSuite) TestReader_ReceiveMessages() {
 	msg2 := fakes.FakeMessage()
 	msg3 := fakes.FakeMessage()
 
+	delayed := fakes.FakeMessage()
+	delayed.DeliverAt = time.Now().Add(time.Hour)
+
+	binary := fakes.FakeMessage()
+	binary.Metadata = map[string]string{"key": "value"}
+	binary.ContentType = "application/json"
+
 	tests := []struct {
-		name string
-		in   []types.Message
+		name    string
+		in      []types.Message
+		out     []types.Message
+		options []wal.ReadOption
 	}{
 		{
 			name: "single message",
 			in:   types.Messages{msg1},
+			out:  types.Messages{msg1},
 		},
 		{
 			name: "several messages",
 			in:   types.Messages{msg1, msg2, msg3},
+			out:  types.Messages{msg1, msg2, msg3},
+		},
+		{
+			name: "delayed message is skipped",
+			in:   types.Messages{delayed, msg1},
+			out:  types.Messages{msg1},
+		},
+		{
+			name:    "binary format",
+			in:      types.Messages{binary, msg1},
+			out:     types.Messages{binary, msg1},
+			options: []wal.ReadOption{wal.WithBinaryFormat()},
 		},
 		// Add more test cases as needed
 	}
@@ -126,7 +149,7 @@ func (suite *ReaderTestSuite) TestReader_ReceiveMessages() {
 		suite.Run(tt.name, func() {
 			t := suite.T()
 
-			reader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", "slot")
+			reader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", "slot", tt.options...)
 			require.NoError(t, err)
 
 			msgCh, errCh, err := reader.Start(ctx)
@@ -146,7 +169,7 @@ func (suite *ReaderTestSuite) TestReader_ReceiveMessages() {
 
 				actual = append(actual, message)
 
-				if len(actual) == len(tt.in) {
+				if len(actual) == len(tt.out) {
 					reader.Close()
 				}
 			}
@@ -156,7 +179,7 @@ func (suite *ReaderTestSuite) TestReader_ReceiveMessages() {
 				suite.noError(err)
 			}
 
-			assertEqualMessages(t, tt.in, actual)
+			assertEqualMessages(t, tt.out, actual)
 		})
 	}
 }
@@ -215,6 +238,328 @@ func (suite *ReaderTestSuite) TestReader_ReceiveMessages_WithToxic() {
 	}
 }
 
+func (suite *ReaderTestSuite) TestReader_Reconnect() {
+	t := suite.T()
+
+	const slot = "slot_reconnect"
+	defer suite.dropReplicationSlot(slot)
+
+	msg1 := fakes.FakeMessage()
+	msg2 := fakes.FakeMessage()
+
+	reader, err := wal.NewReader(suite.readerConnStr+"&connect_timeout=1", outboxTable, "publication", slot,
+		wal.WithStandbyTimeout(100*time.Millisecond),
+		wal.WithPermanentSlot(),
+		wal.WithReconnect(100*time.Millisecond, time.Second, 0))
+	require.NoError(t, err)
+
+	msgCh, errCh, err := reader.Start(ctx)
+	require.NoError(t, err)
+
+	// GIVEN
+	_, err = suite.write(msg1)
+	require.NoError(t, err)
+
+	actual := []types.Message{suite.receive(msgCh)}
+
+	// WHEN the connection is broken
+	toxic, err := suite.toxiProxyProxy.AddToxic("reconnect", "timeout", "downstream", 1.0,
+		toxiproxy.Attributes{"timeout": 0})
+	require.NoError(t, err)
+
+	_, err = suite.write(msg2)
+	require.NoError(t, err)
+
+	time.Sleep(time.Second)
+	require.NoError(t, suite.toxiProxyProxy.RemoveToxic(toxic.Name))
+
+	// THEN the message written during the outage is received after reconnect
+	actual = append(actual, suite.receive(msgCh))
+
+	reader.Close()
+
+	for err := range errCh {
+		suite.noError(err)
+	}
+
+	assertEqualMessages(t, []types.Message{msg1, msg2}, actual)
+}
+
+func (suite *ReaderTestSuite) TestReader_ReconnectAttemptsExhausted() {
+	t := suite.T()
+
+	const slot = "slot_reconnect_exhausted"
+	defer suite.dropReplicationSlot(slot)
+
+	reader, err := wal.NewReader(suite.readerConnStr+"&connect_timeout=1", outboxTable, "publication", slot,
+		wal.WithStandbyTimeout(100*time.Millisecond),
+		wal.WithPermanentSlot(),
+		wal.WithReconnect(10*time.Millisecond, 100*time.Millisecond, 2))
+	require.NoError(t, err)
+
+	_, errCh, err := reader.Start(ctx)
+	require.NoError(t, err)
+
+	toxic, err := suite.toxiProxyProxy.AddToxic("reconnect_exhausted", "timeout", "downstream", 1.0,
+		toxiproxy.Attributes{"timeout": 0})
+	require.NoError(t, err)
+	defer suite.noError(suite.toxiProxyProxy.RemoveToxic(toxic.Name))
+
+	// THEN
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, wal.ErrReconnectAttemptsExhausted)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for expected error")
+	}
+}
+
+func (suite *ReaderTestSuite) TestReader_ReconnectBackpressureError() {
+	t := suite.T()
+
+	const slot = "slot_reconnect_backpressure"
+	defer suite.dropReplicationSlot(slot)
+
+	reader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", slot,
+		wal.WithChannelBuffer(1),
+		wal.WithStandbyTimeout(100*time.Millisecond),
+		wal.WithPermanentSlot(),
+		wal.WithBackpressure(wal.BackpressureError),
+		wal.WithReconnect(10*time.Millisecond, 100*time.Millisecond, 0))
+	require.NoError(t, err)
+
+	_, errCh, err := reader.Start(ctx)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	// GIVEN the consumer does not read messages
+	for range 3 {
+		_, err := suite.write(fakes.FakeMessage())
+		require.NoError(t, err)
+	}
+
+	// THEN the reader stops instead of reconnecting
+	select {
+	case err := <-errCh:
+		require.ErrorIs(t, err, wal.ErrChannelFull)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for expected error")
+	}
+}
+
+func (suite *ReaderTestSuite) TestReader_ManualAck() {
+	t := suite.T()
+
+	const slot = "slot_manual_ack"
+	defer suite.dropReplicationSlot(slot)
+
+	start := func() (*wal.Reader, <-chan wal.Message) {
+		reader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", slot,
+			wal.WithStandbyTimeout(100*time.Millisecond), wal.WithPermanentSlot(), wal.WithManualAck())
+		require.NoError(t, err)
+
+		msgCh, _, err := reader.Start(ctx)
+		require.NoError(t, err)
+
+		return reader, msgCh
+	}
+
+	stop := func(reader *wal.Reader) {
+		reader.Close()
+		// wait until Postgres releases the slot
+		suite.Require().Eventually(func() bool {
+			var active bool
+			err := suite.pool.QueryRow(ctx,
+				"SELECT active FROM pg_replication_slots WHERE slot_name = $1", slot).Scan(&active)
+			return err == nil && !active
+		}, 10*time.Second, 100*time.Millisecond)
+	}
+
+	msg1 := fakes.FakeMessage()
+	msg2 := fakes.FakeMessage()
+	msg3 := fakes.FakeMessage()
+
+	// GIVEN a reader which does not ack
+	reader, msgCh := start()
+
+	_, err := suite.write(msg1)
+	require.NoError(t, err)
+
+	_, err = suite.writeBatch([]types.Message{msg2, msg3})
+	require.NoError(t, err)
+
+	received := []types.Message{suite.receive(msgCh), suite.receive(msgCh), suite.receive(msgCh)}
+	assertEqualMessages(t, []types.Message{msg1, msg2, msg3}, received)
+
+	stop(reader)
+
+	// WHEN restarted
+	reader, msgCh = start()
+
+	// THEN unacknowledged messages are received again
+	actual1, lsn1 := suite.receiveLSN(msgCh)
+	actual2, lsn2 := suite.receiveLSN(msgCh)
+	actual3, lsn3 := suite.receiveLSN(msgCh)
+	assertEqualMessages(t, []types.Message{msg1, msg2, msg3}, []types.Message{actual1, actual2, actual3})
+
+	// LSN of a message in the middle of a transaction is the end of the previous transaction
+	assert.Less(t, lsn1, lsn3)
+	assert.Equal(t, lsn1, lsn2)
+
+	// WHEN only the first transaction is acknowledged
+	require.NoError(t, reader.Ack(lsn2))
+	require.ErrorIs(t, reader.Ack(lsn3+1), wal.ErrAckLSNInvalid)
+
+	stop(reader)
+
+	// THEN only the second transaction is received again
+	reader, msgCh = start()
+	defer reader.Close()
+
+	actual2, _ = suite.receiveLSN(msgCh)
+	actual3, lsn3 = suite.receiveLSN(msgCh)
+	assertEqualMessages(t, []types.Message{msg2, msg3}, []types.Message{actual2, actual3})
+
+	require.NoError(t, reader.Ack(lsn3))
+}
+
+func (suite *ReaderTestSuite) TestReader_Backpressure() {
+	tests := []struct {
+		name    string
+		policy  wal.BackpressurePolicy
+		wantErr error
+		wantMin int // minimum number of received messages out of 3
+		wantMax int
+	}{
+		{
+			name:    "block",
+			policy:  wal.BackpressureBlock,
+			wantMin: 3,
+			wantMax: 3,
+		},
+		{
+			name:    "error",
+			policy:  wal.BackpressureError,
+			wantErr: wal.ErrChannelFull,
+			wantMin: 1,
+			wantMax: 1,
+		},
+		{
+			name:    "drop",
+			policy:  wal.BackpressureDrop,
+			wantMin: 1,
+			wantMax: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			t := suite.T()
+
+			reader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", "slot",
+				wal.WithChannelBuffer(1),
+				wal.WithStandbyTimeout(100*time.Millisecond),
+				wal.WithBackpressure(tt.policy))
+			require.NoError(t, err)
+
+			msgCh, errCh, err := reader.Start(ctx)
+			require.NoError(t, err)
+
+			// GIVEN the consumer does not read messages
+			for range 3 {
+				_, err := suite.write(fakes.FakeMessage())
+				require.NoError(t, err)
+			}
+
+			time.Sleep(time.Second)
+
+			// WHEN
+			var received int
+
+		loop:
+			for {
+				select {
+				case _, ok := <-msgCh:
+					if !ok {
+						break loop
+					}
+					received++
+				case <-time.After(time.Second):
+					reader.Close()
+				}
+			}
+
+			// THEN
+			for err := range errCh {
+				if tt.wantErr != nil {
+					require.ErrorIs(t, err, tt.wantErr)
+				} else {
+					suite.noError(err)
+				}
+			}
+
+			assert.GreaterOrEqual(t, received, tt.wantMin)
+			assert.LessOrEqual(t, received, tt.wantMax)
+		})
+	}
+}
+
+func (suite *ReaderTestSuite) TestReader_StartTransactions() {
+	t := suite.T()
+
+	reader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", "slot")
+	require.NoError(t, err)
+	defer reader.Close()
+
+	txCh, _, err := reader.StartTransactions(ctx)
+	require.NoError(t, err)
+
+	msg1 := fakes.FakeMessage()
+	msg2 := fakes.FakeMessage()
+	msg3 := fakes.FakeMessage()
+
+	// GIVEN
+	_, err = suite.write(msg1)
+	require.NoError(t, err)
+
+	_, err = suite.writeBatch([]types.Message{msg2, msg3})
+	require.NoError(t, err)
+
+	// WHEN
+	var txs []wal.Transaction
+	for len(txs) < 2 {
+		select {
+		case tx := <-txCh:
+			txs = append(txs, tx)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout waiting for transaction")
+		}
+	}
+
+	// THEN
+	for idx, expected := range [][]types.Message{{msg1}, {msg2, msg3}} {
+		tx := txs[idx]
+
+		actual, err := tx.ToOutboxMessages()
+		require.NoError(t, err)
+		assertEqualMessages(t, expected, actual)
+
+		assert.NotZero(t, tx.Xid)
+		assert.NotZero(t, tx.CommitLSN)
+		assert.False(t, tx.CommitTime.IsZero())
+		assert.Greater(t, tx.LSN, tx.CommitLSN, "LSN is the end of the transaction")
+
+		for _, message := range tx.Messages {
+			assert.Equal(t, tx.Xid, message.Xid)
+			assert.Equal(t, tx.CommitLSN, message.CommitLSN)
+		}
+		assert.True(t, tx.Messages[len(tx.Messages)-1].Last)
+	}
+
+	assert.NotEqual(t, txs[0].Xid, txs[1].Xid)
+	assert.Less(t, txs[0].LSN, txs[1].LSN)
+}
+
 func (suite *ReaderTestSuite) TestReader_Start() {
 	reader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", "slot")
 	suite.noError(err)
@@ -315,8 +660,13 @@ func assertEqualMessage(t *testing.T, expected, actual types.Message) {
 	t.Helper()
 
 	cmpOptions := cmp.Options{
+		// assigned on write
 		cmp.FilterPath(func(p cmp.Path) bool {
-			return p.Last().String() == ".ID"
+			switch p.Last().String() {
+			case ".ID", ".UUID", ".CreatedAt", ".PublishedAt":
+				return true
+			}
+			return false
 		}, cmp.Ignore()),
 		cmp.Comparer(func(x, y []byte) bool {
 			var xp, yp payload
@@ -392,6 +742,58 @@ func (suite *ReaderTestSuite) write(message types.Message) (_ int64, txErr error
 	return id, nil
 }
 
+func (suite *ReaderTestSuite) receive(msgCh <-chan wal.Message) types.Message {
+	message, _ := suite.receiveLSN(msgCh)
+	return message
+}
+
+func (suite *ReaderTestSuite) receiveLSN(msgCh <-chan wal.Message) (types.Message, pglogrepl.LSN) {
+	t := suite.T()
+	t.Helper()
+
+	select {
+	case rawMsg, ok := <-msgCh:
+		require.True(t, ok, "message channel is closed")
+
+		message, err := rawMsg.ToOutboxMessage()
+		require.NoError(t, err)
+
+		return message, rawMsg.LSN
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for message")
+	}
+
+	return types.Message{}, 0
+}
+
+// dropReplicationSlot drops a permanent slot once Postgres releases it after the reader is closed.
+func (suite *ReaderTestSuite) dropReplicationSlot(slot string) {
+	suite.Require().Eventually(func() bool {
+		_, err := suite.pool.Exec(ctx,
+			"SELECT pg_drop_replication_slot(slot_name) FROM pg_replication_slots WHERE slot_name = $1", slot)
+		return err == nil
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+func (suite *ReaderTestSuite) writeBatch(messages []types.Message) (_ []int64, txErr error) {
+	tx, commitFunc, err := suite.beginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("beginTx: %w", err)
+	}
+	defer func() {
+		if err := commitFunc(txErr); err != nil {
+			txErr = fmt.Errorf("commitFunc: %w", err)
+		}
+	}()
+
+	ids, err := suite.writer.WriteBatch(ctx, tx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("writer.WriteBatch: %w", err)
+	}
+
+	return ids, nil
+}
+
 func (suite *ReaderTestSuite) noError(err error) {
 	suite.T().Helper()
 	suite.Require().NoError(err)
@@ -429,6 +831,26 @@ func (suite *ReaderTestSuite) TestReader_New() {
 			table:   "",
 			wantErr: outbox.ErrTableEmpty,
 		},
+		{
+			name:        "empty table with logical messages",
+			connStr:     "?replication=database",
+			table:       "",
+			publication: "publication",
+			slot:        "slot",
+			options:     []wal.ReadOption{wal.WithLogicalMessages("outbox")},
+			wantErr:     nil,
+		},
+		{
+			name:        "backpressure drop with logical messages",
+			connStr:     "?replication=database",
+			table:       "",
+			publication: "publication",
+			slot:        "slot",
+			options: []wal.ReadOption{
+				wal.WithLogicalMessages("outbox"), wal.WithBackpressure(wal.BackpressureDrop),
+			},
+			wantErr: wal.ErrBackpressureDropUnsupported,
+		},
 		{
 			name:        "empty publication",
 			connStr:     "?replication=database",
@@ -454,9 +876,101 @@ func (suite *ReaderTestSuite) TestReader_New() {
 				wal.WithPermanentSlot(),
 				wal.WithChannelBuffer(10),
 				wal.WithStandbyTimeout(time.Second),
+				wal.WithReconnect(time.Second, time.Minute, 0),
+				wal.WithManualAck(),
+				wal.WithBackpressure(wal.BackpressureDrop),
+				wal.WithProtocolVersion(4),
+				wal.WithStreaming(1_000),
+				wal.WithTwoPhase(),
+				wal.WithBinaryFormat(),
+				wal.WithOperations(wal.OperationInsert, wal.OperationUpdate, wal.OperationDelete, wal.OperationTruncate),
+				wal.WithTables("outbox_messages_billing"),
+				wal.WithFilter(types.MessageFilter{Brokers: []string{"sns"}, Topics: []string{"orders"}}),
+				wal.WithColumns("id", "broker", "topic", "payload"),
 			},
 			wantErr: nil,
 		},
+		{
+			name:        "invalid backpressure policy",
+			connStr:     "?replication=database",
+			table:       "outbox_messages",
+			publication: "publication",
+			slot:        "slot",
+			options:     []wal.ReadOption{wal.WithBackpressure(wal.BackpressurePolicy(42))},
+			wantErr:     wal.ErrBackpressurePolicyInvalid,
+		},
+		{
+			name:        "invalid protocol version",
+			connStr:     "?replication=database",
+			table:       "outbox_messages",
+			publication: "publication",
+			slot:        "slot",
+			options:     []wal.ReadOption{wal.WithProtocolVersion(5)},
+			wantErr:     wal.ErrProtocolVersionInvalid,
+		},
+		{
+			name:        "two-phase with protocol version 2",
+			connStr:     "?replication=database",
+			table:       "outbox_messages",
+			publication: "publication",
+			slot:        "slot",
+			options:     []wal.ReadOption{wal.WithTwoPhase()},
+			wantErr:     wal.ErrProtocolVersionInvalid,
+		},
+		{
+			name:        "tables without table",
+			connStr:     "?replication=database",
+			table:       "",
+			publication: "publication",
+			slot:        "slot",
+			options:     []wal.ReadOption{wal.WithTables("orders_outbox", "billing_outbox")},
+			wantErr:     nil,
+		},
+		{
+			name:        "empty table in tables",
+			connStr:     "?replication=database",
+			table:       "outbox_messages",
+			publication: "publication",
+			slot:        "slot",
+			options:     []wal.ReadOption{wal.WithTables("")},
+			wantErr:     outbox.ErrTableEmpty,
+		},
+		{
+			name:        "empty operations",
+			connStr:     "?replication=database",
+			table:       "outbox_messages",
+			publication: "publication",
+			slot:        "slot",
+			options:     []wal.ReadOption{wal.WithOperations()},
+			wantErr:     wal.ErrOperationInvalid,
+		},
+		{
+			name:        "unknown operation",
+			connStr:     "?replication=database",
+			table:       "outbox_messages",
+			publication: "publication",
+			slot:        "slot",
+			options:     []wal.ReadOption{wal.WithOperations("upsert")},
+			wantErr:     wal.ErrOperationInvalid,
+		},
+		{
+			name:        "reconnect without permanent slot",
+			connStr:     "?replication=database",
+			table:       "outbox_messages",
+			publication: "publication",
+			slot:        "slot",
+			options:     []wal.ReadOption{wal.WithReconnect(time.Second, time.Minute, 0)},
+			wantErr:     wal.ErrReconnectPermanentSlotRequired,
+		},
+		{
+			name:        "reconnect max backoff less than initial",
+			connStr:     "?replication=database",
+			table:       "outbox_messages",
+			publication: "publication",
+			slot:        "slot",
+			options:     []wal.ReadOption{wal.WithPermanentSlot(), wal.WithReconnect(time.Minute, time.Second, 0)},
+			wantErr:     wal.ErrReconnectBackoffInvalid,
+		},
 	}
 
 	for _, tt := range tests {
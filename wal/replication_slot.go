@@ -8,31 +8,38 @@ import (
 )
 
 //nolint:nonamedreturns
-func (r *Reader) replicationSlotExists(ctx context.Context) (exists bool, active bool, _ error) {
-	query := fmt.Sprintf("SELECT active FROM pg_replication_slots WHERE slot_name = '%s'", r.slot)
+func (r *Reader) replicationSlotExists(ctx context.Context) (exists bool, active bool, confirmed pglogrepl.LSN, _ error) {
+	query := fmt.Sprintf("SELECT active, confirmed_flush_lsn FROM pg_replication_slots WHERE slot_name = '%s'", r.slot)
 
 	result := r.getConn().Exec(ctx, query)
 	defer closeResource("replication_slot_exists_query_result", result)
 
 	row, err := toRow(result)
 	if err != nil {
-		return false, false, fmt.Errorf("toRow: %w", err)
+		return false, false, 0, fmt.Errorf("toRow: %w", err)
 	}
 
 	if len(row) == 0 {
-		return false, false, nil
+		return false, false, 0, nil
+	}
+
+	if len(row) > 1 && len(row[1]) > 0 {
+		confirmed, err = pglogrepl.ParseLSN(string(row[1]))
+		if err != nil {
+			return false, false, 0, fmt.Errorf("pglogrepl.ParseLSN: %w", err)
+		}
 	}
 
 	if len(row[0]) > 0 {
 		// 't' is true, 'f' is false
-		return true, row[0][0] == 't', nil
+		return true, row[0][0] == 't', confirmed, nil
 	}
 
-	return true, false, nil
+	return true, false, confirmed, nil
 }
 
 func (r *Reader) startReplication(ctx context.Context) error {
-	exists, active, err := r.replicationSlotExists(ctx)
+	exists, active, confirmed, err := r.replicationSlotExists(ctx)
 	if err != nil {
 		return fmt.Errorf("replicationSlotExists: %w", err)
 	}
@@ -52,21 +59,51 @@ func (r *Reader) startReplication(ctx context.Context) error {
 		return fmt.Errorf("pglogrepl.IdentifySystem: %w", err)
 	}
 
-	r.lastReceivedLSN = sysIdent.XLogPos
-	r.updateLastProcessedLSN(sysIdent.XLogPos)
+	// resume from the last processed LSN on reconnect, or from the confirmed LSN of the existing permanent slot,
+	// otherwise start from the current WAL position
+	startLSN := pglogrepl.LSN(r.lastProcessedLSN.Load())
+	if startLSN == 0 {
+		startLSN = confirmed
+	}
+	if startLSN == 0 {
+		startLSN = sysIdent.XLogPos
+	}
+
+	r.lastReceivedLSN = startLSN
+	r.lastCommitLSN = startLSN
+	r.inTransaction = false
+	r.held = nil
+	r.resetBuffers()
+	updateLSN(&r.lastEmittedLSN, startLSN)
+	r.updateLastProcessedLSN(startLSN)
 
 	pluginArguments := []string{
-		"proto_version '2'", // pglogrepl does not support 3 or 4 at the moment
+		fmt.Sprintf("proto_version '%d'", r.protocolVersion),
 		fmt.Sprintf("publication_names '%s'", r.publication),
-		"messages 'false'",  // pg_logical_emit_message() is not used
-		"streaming 'false'", // receive only committed transactions
+		fmt.Sprintf("messages '%s'", onOff(r.logicalPrefix != "")), // pg_logical_emit_message()
+		fmt.Sprintf("streaming '%s'", onOff(r.streaming)),
+	}
+
+	if r.twoPhase {
+		pluginArguments = append(pluginArguments, "two_phase 'on'")
+	}
+
+	if r.binary {
+		pluginArguments = append(pluginArguments, "binary 'true'")
 	}
 
 	// no need to specify timeline, as 0 means current Postgres server timeline
-	if err := pglogrepl.StartReplication(ctx, r.getConn(), r.slot, sysIdent.XLogPos,
+	if err := pglogrepl.StartReplication(ctx, r.getConn(), r.slot, startLSN,
 		pglogrepl.StartReplicationOptions{PluginArgs: pluginArguments}); err != nil {
 		return fmt.Errorf("pglogrepl.StartReplication: %w", err)
 	}
 
 	return nil
 }
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,101 @@
+package wal
+
+import (
+	"context"
+	"fmt"
+
+	outbox "github.com/nikolayk812/pgx-outbox"
+)
+
+// Handler handles a message dispatched by Router.
+type Handler func(ctx context.Context, message Message) error
+
+// Router dispatches messages of a Reader watching several tables, see WithTables, to handlers per table.
+type Router struct {
+	reader   *Reader
+	handlers map[string]Handler
+	fallback Handler
+}
+
+// NewRouter keys handlers by table name, either qualified by schema, i.e. "sales.outbox_messages",
+// or not, i.e. "outbox_messages", then it matches the table in any schema.
+func NewRouter(reader *Reader, handlers map[string]Handler, opts ...RouteOption) (*Router, error) {
+	if reader == nil {
+		return nil, outbox.ErrReaderNil
+	}
+	if len(handlers) == 0 {
+		return nil, ErrHandlersEmpty
+	}
+	for table, handler := range handlers {
+		if handler == nil {
+			return nil, fmt.Errorf("%w: table[%s]", ErrHandlerNil, table)
+		}
+	}
+
+	rt := &Router{
+		reader:   reader,
+		handlers: handlers,
+	}
+
+	for _, opt := range opts {
+		opt(rt)
+	}
+
+	return rt, nil
+}
+
+// Run starts the reader and routes its messages until the reader is closed or a handler fails.
+// With WithManualAck option of the reader, a message is acknowledged once it is handled.
+// returns an error if
+// - ctx is done
+// - the reader is closed or fails
+// - routing or handling fails
+// - acknowledging fails.
+func (rt *Router) Run(ctx context.Context) error {
+	messageCh, errorCh, err := rt.reader.Start(ctx)
+	if err != nil {
+		return fmt.Errorf("reader.Start: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case message, ok := <-messageCh:
+			if !ok {
+				return readerClosedError(errorCh)
+			}
+
+			if err := rt.Route(ctx, message); err != nil {
+				return fmt.Errorf("Route lsn[%s]: %w", message.LSN, err)
+			}
+
+			if rt.reader.manualAck {
+				if err := rt.reader.Ack(message.LSN); err != nil {
+					return fmt.Errorf("reader.Ack: %w", err)
+				}
+			}
+		}
+	}
+}
+
+// Route calls the handler of the message table, the default handler if there is none,
+// returns ErrHandlerNotFound if there is no default handler either.
+func (rt *Router) Route(ctx context.Context, message Message) error {
+	handler, ok := rt.handlers[message.Schema+"."+message.Table]
+	if !ok {
+		handler, ok = rt.handlers[message.Table]
+	}
+	if !ok {
+		handler = rt.fallback
+	}
+	if handler == nil {
+		return fmt.Errorf("%w: table[%s.%s]", ErrHandlerNotFound, message.Schema, message.Table)
+	}
+
+	if err := handler(ctx, message); err != nil {
+		return fmt.Errorf("handler table[%s.%s]: %w", message.Schema, message.Table, err)
+	}
+
+	return nil
+}
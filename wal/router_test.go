@@ -0,0 +1,189 @@
+package wal_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/internal/fakes"
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/nikolayk812/pgx-outbox/wal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Route(t *testing.T) {
+	t.Parallel()
+
+	reader, err := wal.NewReader("?replication=database", "outbox_messages", "publication", "slot")
+	require.NoError(t, err)
+
+	var routed []string
+
+	handler := func(name string) wal.Handler {
+		return func(_ context.Context, _ wal.Message) error {
+			routed = append(routed, name)
+			return nil
+		}
+	}
+
+	errHandler := errors.New("handler error")
+
+	router, err := wal.NewRouter(reader, map[string]wal.Handler{
+		"sales.outbox_messages": handler("sales"),
+		"outbox_messages":       handler("any"),
+		"failing":               func(context.Context, wal.Message) error { return errHandler },
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, router.Route(context.Background(), wal.Message{Schema: "sales", Table: "outbox_messages"}))
+	require.NoError(t, router.Route(context.Background(), wal.Message{Schema: "public", Table: "outbox_messages"}))
+	assert.Equal(t, []string{"sales", "any"}, routed)
+
+	err = router.Route(context.Background(), wal.Message{Schema: "public", Table: "failing"})
+	require.ErrorIs(t, err, errHandler)
+
+	err = router.Route(context.Background(), wal.Message{Schema: "public", Table: "unknown"})
+	require.ErrorIs(t, err, wal.ErrHandlerNotFound)
+
+	// WHEN default handler is set
+	router, err = wal.NewRouter(reader, map[string]wal.Handler{"outbox_messages": handler("any")},
+		wal.WithDefaultHandler(handler("default")))
+	require.NoError(t, err)
+
+	require.NoError(t, router.Route(context.Background(), wal.Message{Schema: "public", Table: "unknown"}))
+	assert.Equal(t, []string{"sales", "any", "default"}, routed)
+}
+
+func TestNewRouter(t *testing.T) {
+	t.Parallel()
+
+	reader, err := wal.NewReader("?replication=database", "outbox_messages", "publication", "slot")
+	require.NoError(t, err)
+
+	noop := func(context.Context, wal.Message) error { return nil }
+
+	tests := []struct {
+		name     string
+		reader   *wal.Reader
+		handlers map[string]wal.Handler
+		wantErr  error
+	}{
+		{
+			name:     "nil reader",
+			handlers: map[string]wal.Handler{"outbox_messages": noop},
+			wantErr:  outbox.ErrReaderNil,
+		},
+		{
+			name:    "empty handlers",
+			reader:  reader,
+			wantErr: wal.ErrHandlersEmpty,
+		},
+		{
+			name:     "nil handler",
+			reader:   reader,
+			handlers: map[string]wal.Handler{"outbox_messages": nil},
+			wantErr:  wal.ErrHandlerNil,
+		},
+		{
+			name:     "valid",
+			reader:   reader,
+			handlers: map[string]wal.Handler{"outbox_messages": noop},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			router, err := wal.NewRouter(tt.reader, tt.handlers)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, router)
+		})
+	}
+}
+
+func (suite *ReaderTestSuite) TestRouter_Run() {
+	t := suite.T()
+
+	const billingTable = "outbox_messages_billing"
+
+	_, err := suite.pool.Exec(ctx, `CREATE TABLE `+billingTable+` (LIKE `+outboxTable+` INCLUDING ALL)`)
+	require.NoError(t, err)
+	defer func() {
+		_, err := suite.pool.Exec(ctx, `DROP TABLE `+billingTable)
+		suite.noError(err)
+	}()
+
+	billingWriter, err := outbox.NewWriter(billingTable)
+	require.NoError(t, err)
+
+	reader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication_tables", "slot_tables",
+		wal.WithTables(billingTable), wal.WithManualAck())
+	require.NoError(t, err)
+	defer reader.Close()
+
+	var (
+		mu      sync.Mutex
+		byTable = map[string][]types.Message{}
+	)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	handler := func(_ context.Context, message wal.Message) error {
+		msg, err := message.ToOutboxMessage()
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		byTable[message.Table] = append(byTable[message.Table], msg)
+		if len(byTable[outboxTable]) == 2 && len(byTable[billingTable]) == 1 {
+			cancel()
+		}
+
+		return nil
+	}
+
+	router, err := wal.NewRouter(reader, map[string]wal.Handler{
+		"public." + outboxTable: handler,
+		billingTable:            handler,
+	})
+	require.NoError(t, err)
+
+	// GIVEN
+	msg1 := fakes.FakeMessage()
+	msg2 := fakes.FakeMessage()
+	billing := fakes.FakeMessage()
+
+	_, err = suite.write(msg1)
+	require.NoError(t, err)
+
+	_, err = billingWriter.Write(ctx, suite.pool, billing)
+	require.NoError(t, err)
+
+	_, err = suite.write(msg2)
+	require.NoError(t, err)
+
+	// WHEN
+	err = router.Run(runCtx)
+
+	// THEN
+	require.ErrorIs(t, err, context.Canceled)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assertEqualMessages(t, []types.Message{msg1, msg2}, byTable[outboxTable])
+	assertEqualMessages(t, []types.Message{billing}, byTable[billingTable])
+}
@@ -0,0 +1,170 @@
+package wal_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nikolayk812/pgx-outbox/internal/fakes"
+	"github.com/nikolayk812/pgx-outbox/types"
+	"github.com/nikolayk812/pgx-outbox/wal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (suite *ReaderTestSuite) TestReader_Streaming() {
+	t := suite.T()
+
+	const slot = "slot_streaming"
+
+	// for transactions to be streamed before commit
+	_, err := suite.pool.Exec(ctx, "ALTER SYSTEM SET logical_decoding_work_mem = '64kB'")
+	require.NoError(t, err)
+	_, err = suite.pool.Exec(ctx, "SELECT pg_reload_conf()")
+	require.NoError(t, err)
+	defer func() {
+		_, err := suite.pool.Exec(ctx, "ALTER SYSTEM RESET logical_decoding_work_mem")
+		suite.noError(err)
+		_, err = suite.pool.Exec(ctx, "SELECT pg_reload_conf()")
+		suite.noError(err)
+	}()
+
+	reader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", slot,
+		wal.WithProtocolVersion(4), wal.WithStreaming(50))
+	require.NoError(t, err)
+	defer reader.Close()
+
+	msgCh, _, err := reader.Start(ctx)
+	require.NoError(t, err)
+
+	largeMessages := func(count int) []types.Message {
+		messages := make([]types.Message, 0, count)
+		for range count {
+			message := fakes.FakeMessage()
+			message.Payload = []byte(fmt.Sprintf(`{"content":%q}`, strings.Repeat("x", 1024)))
+			messages = append(messages, message)
+		}
+		return messages
+	}
+
+	committed := largeMessages(200)
+	aborted := largeMessages(100)
+	last := fakes.FakeMessage()
+
+	// GIVEN a rolled back transaction
+	tx, err := suite.pool.Begin(ctx)
+	require.NoError(t, err)
+	_, err = suite.writer.WriteBatch(ctx, tx, largeMessages(200))
+	require.NoError(t, err)
+	require.NoError(t, tx.Rollback(ctx))
+
+	// AND a committed transaction with a rolled back subtransaction
+	tx, err = suite.pool.Begin(ctx)
+	require.NoError(t, err)
+	_, err = suite.writer.WriteBatch(ctx, tx, committed)
+	require.NoError(t, err)
+	_, err = tx.Exec(ctx, "SAVEPOINT aborted")
+	require.NoError(t, err)
+	_, err = suite.writer.WriteBatch(ctx, tx, aborted)
+	require.NoError(t, err)
+	_, err = tx.Exec(ctx, "ROLLBACK TO SAVEPOINT aborted")
+	require.NoError(t, err)
+	_, err = suite.writer.Write(ctx, tx, last)
+	require.NoError(t, err)
+	require.NoError(t, tx.Commit(ctx))
+
+	// WHEN
+	var actual []wal.Message
+	for range len(committed) + 1 {
+		select {
+		case message := <-msgCh:
+			actual = append(actual, message)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("timeout waiting for message, received %d", len(actual))
+		}
+	}
+
+	// THEN only messages of the committed transaction are received
+	expected := append(committed, last) //nolint:gocritic
+	for idx, message := range actual {
+		outboxMessage, err := message.ToOutboxMessage()
+		require.NoError(t, err)
+		assertEqualMessage(t, expected[idx], outboxMessage)
+
+		assert.Equal(t, actual[0].Xid, message.Xid)
+		assert.Equal(t, idx == len(actual)-1, message.Last)
+	}
+
+	select {
+	case message := <-msgCh:
+		t.Fatalf("unexpected message: %v", message)
+	case <-time.After(time.Second):
+	}
+
+	// AND the transaction was streamed indeed
+	suite.Require().Eventually(func() bool {
+		var streamed int64
+		err := suite.pool.QueryRow(ctx,
+			"SELECT stream_txns FROM pg_stat_replication_slots WHERE slot_name = $1", slot).Scan(&streamed)
+		return err == nil && streamed > 0
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+func (suite *ReaderTestSuite) TestReader_TwoPhase() {
+	t := suite.T()
+
+	reader, err := wal.NewReader(suite.readerConnStr, outboxTable, "publication", "slot_two_phase",
+		wal.WithProtocolVersion(3), wal.WithTwoPhase())
+	require.NoError(t, err)
+	defer reader.Close()
+
+	msgCh, _, err := reader.Start(ctx)
+	require.NoError(t, err)
+
+	prepare := func(gid string, message types.Message) {
+		conn, err := suite.pool.Acquire(ctx)
+		require.NoError(t, err)
+		defer conn.Release()
+
+		_, err = conn.Exec(ctx, "BEGIN")
+		require.NoError(t, err)
+		_, err = suite.writer.Write(ctx, conn.Conn(), message)
+		require.NoError(t, err)
+		_, err = conn.Exec(ctx, fmt.Sprintf("PREPARE TRANSACTION '%s'", gid))
+		require.NoError(t, err)
+	}
+
+	msg1 := fakes.FakeMessage()
+	msg2 := fakes.FakeMessage()
+	msg3 := fakes.FakeMessage()
+	msg4 := fakes.FakeMessage()
+
+	// GIVEN a prepared transaction
+	prepare("outbox_commit", msg1)
+
+	// WHEN another transaction is committed meanwhile
+	_, err = suite.write(msg2)
+	require.NoError(t, err)
+
+	// THEN it is received first
+	assertEqualMessage(t, msg2, suite.receive(msgCh))
+
+	// WHEN the prepared transaction is committed
+	_, err = suite.pool.Exec(ctx, "COMMIT PREPARED 'outbox_commit'")
+	require.NoError(t, err)
+
+	// THEN
+	assertEqualMessage(t, msg1, suite.receive(msgCh))
+
+	// WHEN a prepared transaction is rolled back
+	prepare("outbox_rollback", msg3)
+
+	_, err = suite.pool.Exec(ctx, "ROLLBACK PREPARED 'outbox_rollback'")
+	require.NoError(t, err)
+
+	_, err = suite.write(msg4)
+	require.NoError(t, err)
+
+	// THEN its message is not received
+	assertEqualMessage(t, msg4, suite.receive(msgCh))
+}
@@ -0,0 +1,171 @@
+package wal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pglogrepl"
+)
+
+// two-phase commit messages of pgoutput protocol v3, they are not supported by pglogrepl.
+const (
+	messageTypeBeginPrepare     = 'b'
+	messageTypePrepare          = 'P'
+	messageTypeCommitPrepared   = 'K'
+	messageTypeRollbackPrepared = 'r'
+	messageTypeStreamPrepare    = 'p'
+)
+
+// microseconds between Unix epoch and Postgres epoch 2000-01-01.
+const postgresEpochMicros = 946_684_800_000_000
+
+// prepareMessage is BeginPrepare, Prepare or StreamPrepare message.
+type prepareMessage struct {
+	PrepareLSN  pglogrepl.LSN
+	EndLSN      pglogrepl.LSN
+	PrepareTime time.Time
+	Xid         uint32
+	GID         string
+}
+
+type commitPreparedMessage struct {
+	CommitLSN  pglogrepl.LSN
+	EndLSN     pglogrepl.LSN
+	CommitTime time.Time
+	Xid        uint32
+	GID        string
+}
+
+type rollbackPreparedMessage struct {
+	PrepareEndLSN  pglogrepl.LSN
+	RollbackEndLSN pglogrepl.LSN
+	Xid            uint32
+	GID            string
+}
+
+// preparedTx is a transaction prepared for two-phase commit, waiting for COMMIT PREPARED or ROLLBACK PREPARED.
+type preparedTx struct {
+	prepareLSN pglogrepl.LSN
+	buffer     *txBuffer
+}
+
+func isTwoPhaseMessage(walData []byte) bool {
+	switch walData[0] {
+	case messageTypeBeginPrepare, messageTypePrepare, messageTypeCommitPrepared,
+		messageTypeRollbackPrepared, messageTypeStreamPrepare:
+		return true
+	}
+
+	return false
+}
+
+// twoPhaseDecoder decodes fields of two-phase messages in order, the first error is kept.
+type twoPhaseDecoder struct {
+	src []byte
+	err error
+}
+
+func (d *twoPhaseDecoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.src) < n {
+		d.err = fmt.Errorf("message is too short: expected %d more bytes, got %d", n, len(d.src))
+		return nil
+	}
+
+	b := d.src[:n]
+	d.src = d.src[n:]
+
+	return b
+}
+
+func (d *twoPhaseDecoder) skip(n int) {
+	d.next(n)
+}
+
+func (d *twoPhaseDecoder) lsn() pglogrepl.LSN {
+	if b := d.next(8); b != nil {
+		return pglogrepl.LSN(binary.BigEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (d *twoPhaseDecoder) time() time.Time {
+	if b := d.next(8); b != nil {
+		micros := int64(binary.BigEndian.Uint64(b)) + postgresEpochMicros //nolint:gosec
+		return time.UnixMicro(micros).UTC()
+	}
+	return time.Time{}
+}
+
+func (d *twoPhaseDecoder) uint32() uint32 {
+	if b := d.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (d *twoPhaseDecoder) string() string {
+	if d.err != nil {
+		return ""
+	}
+
+	end := bytes.IndexByte(d.src, 0)
+	if end < 0 {
+		d.err = fmt.Errorf("string is not null-terminated")
+		return ""
+	}
+
+	return string(d.next(end + 1)[:end])
+}
+
+// parsePrepare parses BeginPrepare, Prepare or StreamPrepare message, the latter two have flags.
+func parsePrepare(walData []byte) (prepareMessage, error) {
+	d := twoPhaseDecoder{src: walData[1:]}
+	if walData[0] != messageTypeBeginPrepare {
+		d.skip(1) // flags, currently unused
+	}
+
+	msg := prepareMessage{
+		PrepareLSN:  d.lsn(),
+		EndLSN:      d.lsn(),
+		PrepareTime: d.time(),
+		Xid:         d.uint32(),
+		GID:         d.string(),
+	}
+
+	return msg, d.err
+}
+
+func parseCommitPrepared(walData []byte) (commitPreparedMessage, error) {
+	d := twoPhaseDecoder{src: walData[1:]}
+	d.skip(1) // flags, currently unused
+
+	msg := commitPreparedMessage{
+		CommitLSN:  d.lsn(),
+		EndLSN:     d.lsn(),
+		CommitTime: d.time(),
+		Xid:        d.uint32(),
+		GID:        d.string(),
+	}
+
+	return msg, d.err
+}
+
+func parseRollbackPrepared(walData []byte) (rollbackPreparedMessage, error) {
+	d := twoPhaseDecoder{src: walData[1:]}
+	d.skip(1) // flags, currently unused
+
+	msg := rollbackPreparedMessage{
+		PrepareEndLSN:  d.lsn(),
+		RollbackEndLSN: d.lsn(),
+	}
+	d.skip(16) // prepare and rollback timestamps
+	msg.Xid = d.uint32()
+	msg.GID = d.string()
+
+	return msg, d.err
+}
@@ -0,0 +1,151 @@
+package wal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// txBuffer keeps insert messages of a streamed or prepared transaction until it is committed,
+// messages beyond the spill threshold are moved to a temporary file.
+type txBuffer struct {
+	records        []txRecord
+	spillThreshold int // zero or negative never spills
+
+	file    *os.File
+	spilled int
+
+	aborted map[uint32]struct{} // subtransactions
+}
+
+type txRecord struct {
+	subXid   uint32
+	inStream bool
+	data     []byte
+}
+
+func newTxBuffer(spillThreshold int) *txBuffer {
+	return &txBuffer{
+		spillThreshold: spillThreshold,
+		aborted:        map[uint32]struct{}{},
+	}
+}
+
+// add copies data, as it is owned by the connection.
+func (b *txBuffer) add(subXid uint32, inStream bool, data []byte) error {
+	b.records = append(b.records, txRecord{subXid: subXid, inStream: inStream, data: append([]byte(nil), data...)})
+
+	if b.spillThreshold > 0 && len(b.records) >= b.spillThreshold {
+		if err := b.spill(); err != nil {
+			return fmt.Errorf("spill: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// abort discards messages of the subtransaction, spilled ones are skipped on replay.
+func (b *txBuffer) abort(subXid uint32) {
+	b.aborted[subXid] = struct{}{}
+}
+
+func (b *txBuffer) spill() error {
+	if b.file == nil {
+		file, err := os.CreateTemp("", "pgx-outbox-wal-*")
+		if err != nil {
+			return fmt.Errorf("os.CreateTemp: %w", err)
+		}
+		b.file = file
+	}
+
+	w := bufio.NewWriter(b.file)
+
+	header := make([]byte, 9)
+	for _, record := range b.records {
+		binary.BigEndian.PutUint32(header[0:4], record.subXid)
+		header[4] = 0
+		if record.inStream {
+			header[4] = 1
+		}
+		binary.BigEndian.PutUint32(header[5:9], uint32(len(record.data))) //nolint:gosec
+
+		if _, err := w.Write(header); err != nil {
+			return fmt.Errorf("w.Write[header]: %w", err)
+		}
+		if _, err := w.Write(record.data); err != nil {
+			return fmt.Errorf("w.Write[data]: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("w.Flush: %w", err)
+	}
+
+	b.spilled += len(b.records)
+	b.records = nil
+
+	return nil
+}
+
+// replay calls fn for messages of not aborted subtransactions in the order they were added.
+func (b *txBuffer) replay(fn func(record txRecord) error) error {
+	if b.file != nil {
+		if _, err := b.file.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("file.Seek: %w", err)
+		}
+
+		r := bufio.NewReader(b.file)
+
+		header := make([]byte, 9)
+		for range b.spilled {
+			if _, err := io.ReadFull(r, header); err != nil {
+				return fmt.Errorf("io.ReadFull[header]: %w", err)
+			}
+
+			record := txRecord{
+				subXid:   binary.BigEndian.Uint32(header[0:4]),
+				inStream: header[4] == 1,
+				data:     make([]byte, binary.BigEndian.Uint32(header[5:9])),
+			}
+			if _, err := io.ReadFull(r, record.data); err != nil {
+				return fmt.Errorf("io.ReadFull[data]: %w", err)
+			}
+
+			if err := b.replayRecord(record, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, record := range b.records {
+		if err := b.replayRecord(record, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *txBuffer) replayRecord(record txRecord, fn func(record txRecord) error) error {
+	if _, ok := b.aborted[record.subXid]; ok {
+		return nil
+	}
+
+	return fn(record)
+}
+
+// close removes the spill file, if any.
+func (b *txBuffer) close() error {
+	if b.file == nil {
+		return nil
+	}
+
+	name := b.file.Name()
+	closeErr := b.file.Close()
+	b.file = nil
+
+	return errors.Join(closeErr, os.Remove(name))
+}
@@ -2,11 +2,17 @@ package outbox
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/nikolayk812/pgx-outbox/types"
 )
@@ -14,22 +20,59 @@ import (
 // Writer writes outbox messages to a single outbox table.
 // To write messages to multiple outbox tables, create multiple Writer instances.
 // An outbox message must be written in the same transaction as business entities,
-// hence the tx argument which supports both pgx.Tx and *sql.Tx.
+// hence the tx argument which supports both pgx and database/sql transactions, see Querier.
 // Implementations must be safe for concurrent use by multiple goroutines.
 type Writer interface {
 	// Write writes the message to the outbox table.
 	// It returns the ID of the newly inserted message.
-	Write(ctx context.Context, tx Tx, message types.Message) (int64, error)
+	// If a message with the same IdempotencyKey already exists, it returns the ID of the existing message
+	// and an error matching ErrMessageDuplicate, the transaction remains usable.
+	Write(ctx context.Context, tx Querier, message types.Message) (int64, error)
 
 	// WriteBatch writes multiple messages to the outbox table.
 	// It returns the IDs of the newly inserted messages.
 	// It returns an error if any of the messages fail to write.
-	WriteBatch(ctx context.Context, tx pgx.Tx, messages []types.Message) ([]int64, error)
+	// If some messages are duplicates by IdempotencyKey, it returns IDs of all messages, existing ones included,
+	// and *DuplicateError with indexes of duplicates, the transaction remains usable.
+	WriteBatch(ctx context.Context, tx Querier, messages []types.Message) ([]int64, error)
+
+	// WriteBulk writes multiple messages to the outbox table by a single statement.
+	// It returns the IDs of the newly inserted messages in the input order.
+	// It is meant for tens of thousands of messages in a single transaction,
+	// duplicates are handled the same way as by WriteBatch.
+	WriteBulk(ctx context.Context, tx Querier, messages []types.Message) ([]int64, error)
 }
 
+// insertColumns are columns written by Writer, values are returned by insertValues in the same order.
+var insertColumns = []string{
+	"uuid", "broker", "topic", "metadata", "payload", "content_type", "deliver_at", "priority", "idempotency_key",
+}
+
+// reservedMetadataKeys are set by Writer on compression and encryption, not by callers,
+// as they make readers and publishers treat payloads as compressed or encrypted.
+var reservedMetadataKeys = []string{
+	types.MetadataContentEncoding, types.MetadataEncryptionKeyID, types.MetadataEncryptionDataKey,
+}
+
+// multiRowLimit keeps multi-row INSERT statements under the limit of 65535 parameters.
+const multiRowLimit = 1_000
+
+// defaultBulkThreshold is the number of messages starting from which WriteBatch switches to WriteBulk.
+const defaultBulkThreshold = 1_000
+
 type writer struct {
 	table            string
 	usePreparedBatch bool
+	bulkThreshold    int
+	binaryPayload    bool
+	schemaRegistry   types.SchemaRegistry
+
+	compression          string
+	compressionThreshold int
+
+	encryptor Encryptor
+
+	partitioned bool
 }
 
 func NewWriter(table string, opts ...WriteOption) (Writer, error) {
@@ -40,61 +83,97 @@ func NewWriter(table string, opts ...WriteOption) (Writer, error) {
 	w := &writer{
 		table:            table,
 		usePreparedBatch: true,
+		bulkThreshold:    defaultBulkThreshold,
 	}
 
 	for _, opt := range opts {
 		opt(w)
 	}
 
+	if err := w.validateOptions(); err != nil {
+		return nil, err
+	}
+
 	return w, nil
 }
 
+func (w *writer) validateOptions() error {
+	if w.compression != "" {
+		if w.compression != types.ContentEncodingGzip && w.compression != types.ContentEncodingZstd {
+			return fmt.Errorf("%w: %s", types.ErrContentEncodingUnsupported, w.compression)
+		}
+
+		if !w.binaryPayload {
+			return fmt.Errorf("compression: %w", ErrBinaryPayloadRequired)
+		}
+	}
+
+	if w.encryptor != nil && !w.binaryPayload {
+		return fmt.Errorf("encryption: %w", ErrBinaryPayloadRequired)
+	}
+
+	return nil
+}
+
 // Write returns an error if
-// - tx is nil or unsupported
+// - tx is nil
 // - message is invalid
-// - write operation fails.
-func (w *writer) Write(ctx context.Context, tx Tx, message types.Message) (int64, error) {
+// - write operation fails
+// - message is a duplicate by IdempotencyKey, ID of the existing message is returned as well.
+func (w *writer) Write(ctx context.Context, tx Querier, message types.Message) (int64, error) {
 	if tx == nil {
 		return 0, ErrTxNil
 	}
 
-	if err := message.Validate(); err != nil {
-		return 0, fmt.Errorf("message.Validate: %w", err)
+	if err := w.validate(message); err != nil {
+		return 0, fmt.Errorf("validate: %w", err)
+	}
+
+	message, err := w.prepare(ctx, message)
+	if err != nil {
+		return 0, fmt.Errorf("prepare: %w", err)
 	}
 
 	ib := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
 		Insert(w.table).
-		Columns("broker", "topic", "metadata", "payload").
-		Values(message.Broker, message.Topic, message.Metadata, string(message.Payload)).
-		Suffix("RETURNING id")
+		Columns(insertColumns...).
+		Values(w.insertValues(message)...).
+		Suffix(returningSuffix(message.IdempotencyKey != ""))
 
 	query, args, err := ib.ToSql()
 	if err != nil {
 		return 0, fmt.Errorf("ib.ToSql: %w", err)
 	}
 
-	row, err := queryRow(ctx, tx, query, args...)
-	if err != nil {
-		return 0, fmt.Errorf("queryRow: %w", err)
-	}
-
 	var id int64
-	if err := row.Scan(&id); err != nil {
-		return 0, fmt.Errorf("row.Scan: %w", err)
+	if err := tx.QueryRow(ctx, query, args...).Scan(&id); err != nil {
+		if !isNoRows(err) {
+			return 0, fmt.Errorf("row.Scan: %w", err)
+		}
+
+		// ON CONFLICT DO NOTHING returns no rows
+		existing, err := w.existingIDs(ctx, tx, []string{message.IdempotencyKey})
+		if err != nil {
+			return 0, fmt.Errorf("existingIDs: %w", err)
+		}
+
+		return existing[message.IdempotencyKey], fmt.Errorf("%w: key[%s]", ErrMessageDuplicate, message.IdempotencyKey)
 	}
 
 	return id, nil
 }
 
-// WriteBatch uses batching feature of the pgx driver,
+// WriteBatch uses batching feature of the pgx driver for pgx types,
 // by default it uses prepared statements for batch writes, but it can be disabled using WithDisablePreparedBatch option.
+// Statements are prepared once per connection and query, they are not prepared for *pgxpool.Pool,
+// as it does not support it.
+// For database/sql types adapted by SQL it uses multi-row INSERT statements instead.
 // WriteBatch returns an error if
 // - tx is nil
 // - any message is invalid
-// - write operation fails.
-//
-//nolint:cyclop
-func (w *writer) WriteBatch(ctx context.Context, tx pgx.Tx, messages []types.Message) (_ []int64, txErr error) {
+// - write operation fails
+// - some messages are duplicates by IdempotencyKey, IDs of all messages are returned as well.
+func (w *writer) WriteBatch(ctx context.Context, tx Querier, messages []types.Message) ([]int64, error) {
 	if tx == nil {
 		return nil, ErrTxNil
 	}
@@ -103,26 +182,135 @@ func (w *writer) WriteBatch(ctx context.Context, tx pgx.Tx, messages []types.Mes
 		return nil, nil
 	}
 
-	if err := types.Messages(messages).Validate(); err != nil {
-		return nil, fmt.Errorf("messages.Validate: %w", err)
+	for idx, message := range messages {
+		if err := w.validate(message); err != nil {
+			return nil, fmt.Errorf("validate idx[%d]: %w", idx, err)
+		}
 	}
 
 	if len(messages) == 1 {
 		id, err := w.Write(ctx, tx, messages[0])
+		if errors.Is(err, ErrMessageDuplicate) {
+			return []int64{id}, &DuplicateError{Indexes: []int{0}}
+		}
 		if err != nil {
 			return nil, fmt.Errorf("w.Write: %w", err)
 		}
 		return []int64{id}, nil
 	}
 
-	query := fmt.Sprintf("INSERT INTO %s (broker, topic, metadata, payload) VALUES ($1, $2, $3, $4) RETURNING id", w.table)
+	messages, err := w.prepareAll(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("prepareAll: %w", err)
+	}
 
-	if w.usePreparedBatch {
-		prepareStatementName := fmt.Sprintf("%s_write_batch_%d", w.table, time.Now().UnixMilli())
+	var (
+		ids        []int64
+		duplicates []int
+	)
 
-		_, err := tx.Prepare(ctx, prepareStatementName, query)
+	switch t := tx.(type) {
+	case batcher:
+		if w.bulkThreshold > 0 && len(messages) >= w.bulkThreshold {
+			ids, duplicates, err = w.writeBulk(ctx, tx, messages)
+			if err != nil {
+				return nil, fmt.Errorf("writeBulk: %w", err)
+			}
+			break
+		}
+
+		ids, duplicates, err = w.writePgxBatch(ctx, t, messages)
 		if err != nil {
-			return nil, fmt.Errorf("tx.Prepare: %w", err)
+			return nil, fmt.Errorf("writePgxBatch: %w", err)
+		}
+	default:
+		ids, duplicates, err = w.writeMultiRow(ctx, tx, messages)
+		if err != nil {
+			return nil, fmt.Errorf("writeMultiRow: %w", err)
+		}
+	}
+
+	return w.resolveDuplicates(ctx, tx, messages, ids, duplicates)
+}
+
+// WriteBulk inserts all messages by a single INSERT ... SELECT FROM unnest(...) statement,
+// passing values of each column as an array parameter.
+// It is faster than WriteBatch for thousands of messages, which switches to WriteBulk automatically
+// for pgx types, see WithBulkThreshold option.
+// For database/sql types adapted by SQL it requires the pgx stdlib driver to pass arrays as parameters.
+// WriteBulk returns the same errors as WriteBatch.
+func (w *writer) WriteBulk(ctx context.Context, tx Querier, messages []types.Message) ([]int64, error) {
+	if tx == nil {
+		return nil, ErrTxNil
+	}
+
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	for idx, message := range messages {
+		if err := w.validate(message); err != nil {
+			return nil, fmt.Errorf("validate idx[%d]: %w", idx, err)
+		}
+	}
+
+	messages, err := w.prepareAll(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("prepareAll: %w", err)
+	}
+
+	ids, duplicates, err := w.writeBulk(ctx, tx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("writeBulk: %w", err)
+	}
+
+	return w.resolveDuplicates(ctx, tx, messages, ids, duplicates)
+}
+
+// resolveDuplicates fills IDs of duplicate messages with IDs of existing ones
+// and returns *DuplicateError if there are any duplicates.
+func (w *writer) resolveDuplicates(ctx context.Context, q Querier,
+	messages []types.Message, ids []int64, duplicates []int,
+) ([]int64, error) {
+	if len(duplicates) == 0 {
+		return ids, nil
+	}
+
+	keys := make([]string, 0, len(duplicates))
+	for _, idx := range duplicates {
+		keys = append(keys, messages[idx].IdempotencyKey)
+	}
+
+	existing, err := w.existingIDs(ctx, q, keys)
+	if err != nil {
+		return nil, fmt.Errorf("existingIDs: %w", err)
+	}
+
+	for _, idx := range duplicates {
+		ids[idx] = existing[messages[idx].IdempotencyKey]
+	}
+
+	return ids, &DuplicateError{Indexes: duplicates}
+}
+
+// writePgxBatch returns IDs of inserted messages in the input order,
+// and indexes of messages which were not inserted due to ON CONFLICT DO NOTHING, their IDs are 0.
+func (w *writer) writePgxBatch(ctx context.Context, b batcher, messages []types.Message) ([]int64, []int, error) {
+	placeholders := make([]string, len(insertColumns))
+	for i := range placeholders {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) %s", w.table,
+		strings.Join(insertColumns, ", "), strings.Join(placeholders, ", "), returningSuffix(idempotent(messages)))
+
+	if p, ok := b.(preparer); ok && w.usePreparedBatch {
+		// the name is stable per query, so pgx reuses the statement prepared on the connection before
+		prepareStatementName := fmt.Sprintf("outbox_write_batch_%x", sha256.Sum256([]byte(query)))
+
+		_, err := p.Prepare(ctx, prepareStatementName, query)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tx.Prepare: %w", err)
 		}
 
 		query = prepareStatementName
@@ -130,46 +318,367 @@ func (w *writer) WriteBatch(ctx context.Context, tx pgx.Tx, messages []types.Mes
 
 	batch := &pgx.Batch{}
 	for _, message := range messages {
-		batch.Queue(query,
-			message.Broker, message.Topic, message.Metadata, string(message.Payload))
+		batch.Queue(query, w.insertValues(message)...)
 	}
 
-	br := tx.SendBatch(ctx, batch)
+	ids, duplicates, err := sendBatch(ctx, b, batch, len(messages))
+	if err != nil {
+		return nil, nil, fmt.Errorf("sendBatch: %w", err)
+	}
+
+	return ids, duplicates, nil
+}
+
+//nolint:nonamedreturns
+func sendBatch(ctx context.Context, b batcher, batch *pgx.Batch, count int) (_ []int64, _ []int, txErr error) {
+	br := b.SendBatch(ctx, batch)
 	defer func() {
 		if err := br.Close(); err != nil {
 			txErr = fmt.Errorf("br.Close: %w", err)
 		}
 	}()
 
+	var duplicates []int
+
 	// Collect all returned IDs
-	ids := make([]int64, 0, len(messages))
-	for range messages {
+	ids := make([]int64, 0, count)
+	for idx := range count {
 		row := br.QueryRow()
 		var id int64
 		if err := row.Scan(&id); err != nil {
-			return nil, fmt.Errorf("row.Scan: %w", err)
+			if !isNoRows(err) {
+				return nil, nil, fmt.Errorf("row.Scan: %w", err)
+			}
+			duplicates = append(duplicates, idx)
 		}
 		ids = append(ids, id)
 	}
 
-	return ids, nil
+	return ids, duplicates, nil
 }
 
-// Tx is a transaction interface to support both and pgx.Tx and *sql.Tx.
-// As pgx.Tx and *sql.Tx do not have common method signatures, this is empty interface.
-type Tx interface{}
+// writeMultiRow writes messages by multi-row INSERT statements of up to multiRowLimit rows,
+// it returns IDs in the input order matching them by UUIDs, as RETURNING order is not guaranteed,
+// and indexes of messages which were not inserted due to ON CONFLICT DO NOTHING, their IDs are 0.
+func (w *writer) writeMultiRow(ctx context.Context, q Querier, messages []types.Message) ([]int64, []int, error) {
+	suffix := returningSuffix(idempotent(messages)) + ", uuid"
 
-func queryRow(ctx context.Context, tx Tx, q string, args ...interface{}) (pgx.Row, error) {
-	var row pgx.Row
+	inserted := make(map[uuid.UUID]int64, len(messages))
 
-	switch t := tx.(type) {
-	case *sql.Tx:
-		row = t.QueryRowContext(ctx, q, args...)
-	case pgx.Tx:
-		row = t.QueryRow(ctx, q, args...)
-	default:
-		return nil, fmt.Errorf("%w: %T", ErrTxUnsupportedType, tx)
+	for chunk := range slices.Chunk(messages, multiRowLimit) {
+		ib := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).
+			Insert(w.table).
+			Columns(insertColumns...).
+			Suffix(suffix)
+
+		for _, message := range chunk {
+			ib = ib.Values(w.insertValues(message)...)
+		}
+
+		query, args, err := ib.ToSql()
+		if err != nil {
+			return nil, nil, fmt.Errorf("ib.ToSql: %w", err)
+		}
+
+		if err := queryRows(ctx, q, func(row pgx.Row) error {
+			var (
+				id  int64
+				key uuid.UUID
+			)
+			if err := row.Scan(&id, &key); err != nil {
+				return fmt.Errorf("row.Scan: %w", err)
+			}
+			inserted[key] = id
+			return nil
+		}, query, args...); err != nil {
+			return nil, nil, fmt.Errorf("queryRows: %w", err)
+		}
+	}
+
+	ids, duplicates := orderByUUID(messages, inserted)
+
+	return ids, duplicates, nil
+}
+
+// writeBulk returns IDs in the input order matching them by UUIDs, as RETURNING order is not guaranteed,
+// and indexes of messages which were not inserted due to ON CONFLICT DO NOTHING, their IDs are 0.
+func (w *writer) writeBulk(ctx context.Context, q Querier, messages []types.Message) ([]int64, []int, error) {
+	var (
+		uuids           = make([]string, 0, len(messages))
+		brokers         = make([]string, 0, len(messages))
+		topics          = make([]string, 0, len(messages))
+		metadatas       = make([]*string, 0, len(messages))
+		contentTypes    = make([]*string, 0, len(messages))
+		deliverAts      = make([]*time.Time, 0, len(messages))
+		priorities      = make([]int16, 0, len(messages))
+		idempotencyKeys = make([]*string, 0, len(messages))
+	)
+
+	for _, message := range messages {
+		md, err := metadata(message)
+		if err != nil {
+			return nil, nil, fmt.Errorf("metadata: %w", err)
+		}
+
+		uuids = append(uuids, message.UUID.String())
+		brokers = append(brokers, message.Broker)
+		topics = append(topics, message.Topic)
+		metadatas = append(metadatas, md)
+		contentTypes = append(contentTypes, contentType(message))
+		deliverAts = append(deliverAts, deliverAt(message))
+		priorities = append(priorities, message.Priority)
+		idempotencyKeys = append(idempotencyKeys, idempotencyKey(message))
+	}
+
+	payloadType := "jsonb"
+	if w.binaryPayload {
+		payloadType = "bytea"
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (%s)
+		SELECT * FROM unnest($1::uuid[], $2::text[], $3::text[], $4::jsonb[], $5::%s[], $6::text[],
+			$7::timestamp[], $8::smallint[], $9::text[])
+		%s, uuid`, w.table, strings.Join(insertColumns, ", "), payloadType, returningSuffix(idempotent(messages)))
+
+	inserted := make(map[uuid.UUID]int64, len(messages))
+
+	if err := queryRows(ctx, q, func(row pgx.Row) error {
+		var (
+			id  int64
+			key uuid.UUID
+		)
+		if err := row.Scan(&id, &key); err != nil {
+			return fmt.Errorf("row.Scan: %w", err)
+		}
+		inserted[key] = id
+		return nil
+	}, query,
+		uuids, brokers, topics, metadatas, w.payloads(messages), contentTypes, deliverAts, priorities, idempotencyKeys); err != nil {
+		return nil, nil, fmt.Errorf("queryRows: %w", err)
+	}
+
+	ids, duplicates := orderByUUID(messages, inserted)
+
+	return ids, duplicates, nil
+}
+
+// orderByUUID returns IDs of inserted messages in the input order,
+// and indexes of messages absent in inserted map.
+func orderByUUID(messages []types.Message, inserted map[uuid.UUID]int64) ([]int64, []int) {
+	var duplicates []int
+
+	ids := make([]int64, 0, len(messages))
+	for idx, message := range messages {
+		id, ok := inserted[message.UUID]
+		if !ok {
+			duplicates = append(duplicates, idx)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, duplicates
+}
+
+// existingIDs returns IDs of messages by their idempotency keys.
+func (w *writer) existingIDs(ctx context.Context, q Querier, keys []string) (map[string]int64, error) {
+	query := fmt.Sprintf("SELECT id, idempotency_key FROM %s WHERE idempotency_key = ANY($1)", w.table)
+
+	result := make(map[string]int64, len(keys))
+
+	if err := queryRows(ctx, q, func(row pgx.Row) error {
+		var (
+			id  int64
+			key string
+		)
+		if err := row.Scan(&id, &key); err != nil {
+			return fmt.Errorf("row.Scan: %w", err)
+		}
+		result[key] = id
+		return nil
+	}, query, keys); err != nil {
+		return nil, fmt.Errorf("queryRows: %w", err)
+	}
+
+	return result, nil
+}
+
+func (w *writer) prepareAll(ctx context.Context, messages []types.Message) ([]types.Message, error) {
+	result := make([]types.Message, 0, len(messages))
+
+	for idx, message := range messages {
+		message, err := w.prepare(ctx, message)
+		if err != nil {
+			return nil, fmt.Errorf("prepare idx[%d]: %w", idx, err)
+		}
+		result = append(result, message)
+	}
+
+	return result, nil
+}
+
+// prepare generates UUID, compresses and encrypts the payload of a validated message before writing.
+// Payloads are compressed before encryption, as ciphertext does not compress.
+func (w *writer) prepare(ctx context.Context, message types.Message) (types.Message, error) {
+	message, err := withUUID(message)
+	if err != nil {
+		return message, fmt.Errorf("withUUID: %w", err)
+	}
+
+	if w.compression != "" && len(message.Payload) >= w.compressionThreshold {
+		message, err = message.Compressed(w.compression)
+		if err != nil {
+			return message, fmt.Errorf("message.Compressed: %w", err)
+		}
+	}
+
+	if w.encryptor != nil {
+		message, err = w.encryptor.Encrypt(ctx, message)
+		if err != nil {
+			return message, fmt.Errorf("encryptor.Encrypt: %w", err)
+		}
+	}
+
+	return message, nil
+}
+
+// withUUID generates UUIDv7 for the message if it is not set,
+// UUIDv7 is time-ordered, so it is index-friendly as well.
+func withUUID(message types.Message) (types.Message, error) {
+	if message.UUID != uuid.Nil {
+		return message, nil
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return message, fmt.Errorf("uuid.NewV7: %w", err)
+	}
+
+	message.UUID = id
+	return message, nil
+}
+
+func (w *writer) insertValues(message types.Message) []interface{} {
+	return []interface{}{
+		message.UUID, message.Broker, message.Topic, message.Metadata, w.payload(message), contentType(message),
+		deliverAt(message), message.Priority, idempotencyKey(message),
+	}
+}
+
+// payload is passed as string to JSONB column, and as []byte to BYTEA column.
+func (w *writer) payload(message types.Message) interface{} {
+	if w.binaryPayload {
+		return message.Payload
+	}
+
+	return string(message.Payload)
+}
+
+// payloads returns payloads as []string for JSONB column, and as [][]byte for BYTEA column.
+func (w *writer) payloads(messages []types.Message) interface{} {
+	if w.binaryPayload {
+		payloads := make([][]byte, 0, len(messages))
+		for _, message := range messages {
+			payloads = append(payloads, message.Payload)
+		}
+		return payloads
+	}
+
+	payloads := make([]string, 0, len(messages))
+	for _, message := range messages {
+		payloads = append(payloads, string(message.Payload))
+	}
+	return payloads
+}
+
+// validate checks the message, that its payload fits the payload column type
+// and matches the schema registered for the message topic.
+func (w *writer) validate(message types.Message) error {
+	if err := message.Validate(); err != nil {
+		return fmt.Errorf("message.Validate: %w", err)
+	}
+
+	for _, key := range reservedMetadataKeys {
+		if _, ok := message.Metadata[key]; ok {
+			return fmt.Errorf("%w: key[%s]", ErrMetadataKeyReserved, key)
+		}
+	}
+
+	if w.partitioned && message.IdempotencyKey != "" {
+		return fmt.Errorf("%w: key[%s]", ErrIdempotencyKeyUnsupported, message.IdempotencyKey)
+	}
+
+	if !w.binaryPayload && !message.IsJSON() {
+		return fmt.Errorf("%w: content_type[%s]", ErrBinaryPayloadRequired, message.ContentType)
+	}
+
+	if err := message.ValidateSchema(w.schemaRegistry); err != nil {
+		return fmt.Errorf("message.ValidateSchema: %w", err)
+	}
+
+	return nil
+}
+
+// returningSuffix skips messages with already existing idempotency key,
+// ON CONFLICT clause is used only when needed as it fails for tables without the unique index.
+func returningSuffix(idempotent bool) string {
+	if idempotent {
+		return "ON CONFLICT (idempotency_key) DO NOTHING RETURNING id"
+	}
+
+	return "RETURNING id"
+}
+
+// idempotencyKey returns nil for empty IdempotencyKey to store NULL,
+// multiple NULLs do not violate the unique index.
+func idempotencyKey(message types.Message) *string {
+	if message.IdempotencyKey == "" {
+		return nil
+	}
+
+	return &message.IdempotencyKey
+}
+
+func idempotent(messages []types.Message) bool {
+	return slices.ContainsFunc(messages, func(m types.Message) bool {
+		return m.IdempotencyKey != ""
+	})
+}
+
+func isNoRows(err error) bool {
+	return errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows)
+}
+
+// metadata returns nil for nil Metadata to store NULL, the same as pgx does for Write.
+func metadata(message types.Message) (*string, error) {
+	if message.Metadata == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	bytes, err := json.Marshal(message.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	s := string(bytes)
+	return &s, nil
+}
+
+// contentType returns nil for empty ContentType to store NULL.
+func contentType(message types.Message) *string {
+	if message.ContentType == "" {
+		return nil
+	}
+
+	return &message.ContentType
+}
+
+// deliverAt returns nil for zero DeliverAt to store NULL,
+// otherwise it is converted to UTC as the deliver_at column is TIMESTAMP without time zone.
+func deliverAt(message types.Message) *time.Time {
+	if message.DeliverAt.IsZero() {
+		return nil
 	}
 
-	return row, nil
+	t := message.DeliverAt.UTC()
+	return &t
 }
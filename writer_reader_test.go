@@ -1,20 +1,28 @@
 package outbox_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"testing"
+	"time"
 
+	"github.com/brianvoe/gofakeit"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	outbox "github.com/nikolayk812/pgx-outbox"
+	"github.com/nikolayk812/pgx-outbox/encryption"
 	"github.com/nikolayk812/pgx-outbox/internal/containers"
 	"github.com/nikolayk812/pgx-outbox/internal/fakes"
+	"github.com/nikolayk812/pgx-outbox/schema/jsonschema"
 	"github.com/nikolayk812/pgx-outbox/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -282,6 +290,642 @@ func (suite *WriterReaderTestSuite) TestWriter_WriteMessagesBatch() {
 	}
 }
 
+func (suite *WriterReaderTestSuite) TestWriter_WriteQuerier() {
+	tests := []struct {
+		name string
+		tx   func(t *testing.T) (outbox.Querier, func())
+	}{
+		{
+			name: "pgxpool.Pool",
+			tx: func(_ *testing.T) (outbox.Querier, func()) {
+				return suite.pool, func() {}
+			},
+		},
+		{
+			name: "pgx.Conn",
+			tx: func(t *testing.T) (outbox.Querier, func()) {
+				t.Helper()
+				conn, err := suite.pool.Acquire(ctx)
+				require.NoError(t, err)
+				return conn.Conn(), conn.Release
+			},
+		},
+		{
+			name: "sql.DB",
+			tx: func(_ *testing.T) (outbox.Querier, func()) {
+				return outbox.SQL(suite.db), func() {}
+			},
+		},
+		{
+			name: "sql.Tx",
+			tx: func(t *testing.T) (outbox.Querier, func()) {
+				t.Helper()
+				tx, err := suite.db.BeginTx(ctx, nil)
+				require.NoError(t, err)
+				return outbox.SQL(tx), func() {
+					require.NoError(t, tx.Commit())
+				}
+			},
+		},
+		{
+			name: "sql.Conn",
+			tx: func(t *testing.T) (outbox.Querier, func()) {
+				t.Helper()
+				conn, err := suite.db.Conn(ctx)
+				require.NoError(t, err)
+				return outbox.SQL(conn), func() {
+					require.NoError(t, conn.Close())
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			t := suite.T()
+
+			in := []types.Message{fakes.FakeMessage(), fakes.FakeMessage(), fakes.FakeMessage()}
+
+			// GIVEN
+			tx, done := tt.tx(t)
+
+			// WHEN
+			id, err := suite.writer.Write(ctx, tx, in[0])
+			require.NoError(t, err)
+			assert.Positive(t, id)
+
+			ids, err := suite.writer.WriteBatch(ctx, tx, in[1:])
+			require.NoError(t, err)
+			require.Len(t, ids, 2)
+			assert.Less(t, id, ids[0])
+			assert.Less(t, ids[0], ids[1])
+
+			done()
+
+			// THEN
+			actual, err := suite.reader.Read(ctx, 10)
+			require.NoError(t, err)
+			assertEqualMessages(t, in, actual)
+
+			suite.markAll()
+		})
+	}
+}
+
+func (suite *WriterReaderTestSuite) TestWriter_WriteBatchPreparedStatements() {
+	t := suite.T()
+
+	conn, err := suite.pool.Acquire(ctx)
+	require.NoError(t, err)
+	defer conn.Release()
+
+	idempotent := func() types.Message {
+		message := fakes.FakeMessage()
+		message.IdempotencyKey = gofakeit.UUID()
+		return message
+	}
+
+	// WHEN plain and idempotent batches are written repeatedly on the same connection
+	for range 3 {
+		_, err = suite.writer.WriteBatch(ctx, conn.Conn(), []types.Message{fakes.FakeMessage(), fakes.FakeMessage()})
+		require.NoError(t, err)
+
+		_, err = suite.writer.WriteBatch(ctx, conn.Conn(), []types.Message{idempotent(), idempotent()})
+		require.NoError(t, err)
+	}
+
+	// THEN a single statement is prepared per query
+	var count int
+	err = conn.QueryRow(ctx,
+		"SELECT count(*) FROM pg_prepared_statements WHERE name LIKE 'outbox_write_batch_%'").Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	suite.markAll()
+}
+
+func (suite *WriterReaderTestSuite) TestWriter_WriteIdempotentStdLib() {
+	t := suite.T()
+
+	msg1 := fakes.FakeMessage()
+	msg1.IdempotencyKey = gofakeit.UUID()
+
+	msg2 := fakes.FakeMessage()
+	msg2.IdempotencyKey = gofakeit.UUID()
+
+	// GIVEN
+	id1, err := suite.writeStdLib(msg1)
+	require.NoError(t, err)
+
+	// WHEN a batch contains an existing key and a repeated key
+	ids, err := suite.writer.WriteBatch(ctx, outbox.SQL(suite.db), []types.Message{msg2, msg1, msg2})
+
+	// THEN duplicates are reported with existing IDs
+	var dupErr *outbox.DuplicateError
+	require.ErrorAs(t, err, &dupErr)
+	assert.Equal(t, []int{1, 2}, dupErr.Indexes)
+
+	require.Len(t, ids, 3)
+	assert.Equal(t, id1, ids[1])
+	assert.Equal(t, ids[0], ids[2])
+
+	actual, err := suite.reader.Read(ctx, 10)
+	require.NoError(t, err)
+	assertEqualMessages(t, []types.Message{msg1, msg2}, actual)
+
+	suite.markAll()
+}
+
+func (suite *WriterReaderTestSuite) TestWriter_WriteBulk() {
+	bulkWriter, err := outbox.NewWriter(outboxTable, outbox.WithBulkThreshold(10))
+	suite.noError(err)
+
+	fakeMessages := func(count int) []types.Message {
+		messages := make([]types.Message, 0, count)
+		for range count {
+			messages = append(messages, fakes.FakeMessage())
+		}
+		return messages
+	}
+
+	existing := fakes.FakeMessage()
+	existing.IdempotencyKey = gofakeit.UUID()
+
+	tests := []struct {
+		name           string
+		in             []types.Message
+		writeFn        func(ctx context.Context, tx outbox.Querier, messages []types.Message) ([]int64, error)
+		tx             outbox.Querier
+		wantDuplicates []int
+	}{
+		{
+			name:    "WriteBulk with pgxpool.Pool",
+			in:      fakeMessages(2500),
+			writeFn: suite.writer.WriteBulk,
+			tx:      suite.pool,
+		},
+		{
+			name:    "WriteBulk with sql.DB",
+			in:      fakeMessages(100),
+			writeFn: suite.writer.WriteBulk,
+			tx:      outbox.SQL(suite.db),
+		},
+		{
+			name:           "WriteBulk with duplicates",
+			in:             append(fakeMessages(3), existing),
+			writeFn:        suite.writer.WriteBulk,
+			tx:             suite.pool,
+			wantDuplicates: []int{3},
+		},
+		{
+			name:    "WriteBatch above threshold",
+			in:      fakeMessages(20),
+			writeFn: bulkWriter.WriteBatch,
+			tx:      suite.pool,
+		},
+	}
+
+	_, err = suite.writer.Write(ctx, suite.pool, existing)
+	suite.noError(err)
+	suite.markAll()
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			t := suite.T()
+
+			// WHEN
+			ids, err := tt.writeFn(ctx, tt.tx, tt.in)
+			if tt.wantDuplicates != nil {
+				var dupErr *outbox.DuplicateError
+				require.ErrorAs(t, err, &dupErr)
+				assert.Equal(t, tt.wantDuplicates, dupErr.Indexes)
+			} else {
+				require.NoError(t, err)
+			}
+
+			// THEN IDs are returned in the input order
+			require.Len(t, ids, len(tt.in))
+			for _, id := range ids {
+				assert.Positive(t, id)
+			}
+			if tt.wantDuplicates == nil {
+				assert.True(t, slices.IsSorted(ids))
+			}
+
+			inserted := slices.DeleteFunc(slices.Clone(tt.in), func(m types.Message) bool {
+				return m.IdempotencyKey == existing.IdempotencyKey
+			})
+
+			actual, err := suite.reader.Read(ctx, len(tt.in))
+			require.NoError(t, err)
+			assertEqualMessages(t, inserted, actual)
+
+			_, err = suite.reader.Ack(ctx, types.Messages(actual).IDs())
+			require.NoError(t, err)
+		})
+	}
+}
+
+func (suite *WriterReaderTestSuite) TestWriter_WriteIdempotent() {
+	t := suite.T()
+
+	msg1 := fakes.FakeMessage()
+	msg1.IdempotencyKey = gofakeit.UUID()
+
+	msg2 := fakes.FakeMessage()
+	msg2.IdempotencyKey = gofakeit.UUID()
+
+	msg3 := fakes.FakeMessage()
+
+	// GIVEN
+	id1, err := suite.write(msg1)
+	require.NoError(t, err)
+
+	// WHEN the same key is written again in another transaction
+	tx, err := suite.pool.Begin(ctx)
+	require.NoError(t, err)
+
+	id, err := suite.writer.Write(ctx, tx, msg1)
+	require.NoError(t, tx.Rollback(ctx))
+
+	// THEN the existing ID is returned
+	require.ErrorIs(t, err, outbox.ErrMessageDuplicate)
+	assert.Equal(t, id1, id)
+
+	// WHEN a batch contains an existing key and a repeated key
+	ids, err := suite.writeBatchIdempotent([]types.Message{msg2, msg1, msg3, msg2})
+
+	// THEN duplicates are reported with existing IDs
+	var dupErr *outbox.DuplicateError
+	require.ErrorAs(t, err, &dupErr)
+	require.ErrorIs(t, err, outbox.ErrMessageDuplicate)
+	assert.Equal(t, []int{1, 3}, dupErr.Indexes)
+
+	require.Len(t, ids, 4)
+	assert.Equal(t, id1, ids[1])
+	assert.Equal(t, ids[0], ids[3])
+
+	// AND each message is stored once
+	actual, err := suite.reader.Read(ctx, 10)
+	require.NoError(t, err)
+	assertEqualMessages(t, []types.Message{msg1, msg2, msg3}, actual)
+
+	suite.markAll()
+}
+
+func (suite *WriterReaderTestSuite) TestWriter_WritePartitionedIdempotent() {
+	t := suite.T()
+
+	const (
+		partitionedTable = "outbox_messages_partitioned"
+		defaultPartition = partitionedTable + "_default"
+	)
+
+	_, err := suite.pool.Exec(ctx, "CREATE TABLE "+defaultPartition+" PARTITION OF "+partitionedTable+" DEFAULT")
+	require.NoError(t, err)
+	defer func() {
+		_, err := suite.pool.Exec(ctx, "DROP TABLE "+defaultPartition)
+		suite.noError(err)
+	}()
+
+	partitionedWriter, err := outbox.NewWriter(partitionedTable, outbox.WithPartitionedTable())
+	require.NoError(t, err)
+
+	idempotent := fakes.FakeMessage()
+	idempotent.IdempotencyKey = gofakeit.UUID()
+
+	// WHEN messages with idempotency key are written
+	_, err = partitionedWriter.Write(ctx, suite.pool, idempotent)
+	require.ErrorIs(t, err, outbox.ErrIdempotencyKeyUnsupported)
+
+	_, err = partitionedWriter.WriteBatch(ctx, suite.pool, []types.Message{fakes.FakeMessage(), idempotent})
+	require.ErrorIs(t, err, outbox.ErrIdempotencyKeyUnsupported)
+
+	_, err = partitionedWriter.WriteBulk(ctx, suite.pool, []types.Message{fakes.FakeMessage(), idempotent})
+	require.ErrorIs(t, err, outbox.ErrIdempotencyKeyUnsupported)
+
+	// THEN messages without idempotency key are written
+	ids, err := partitionedWriter.WriteBatch(ctx, suite.pool, []types.Message{fakes.FakeMessage(), fakes.FakeMessage()})
+	require.NoError(t, err)
+	assert.Len(t, ids, 2)
+}
+
+func (suite *WriterReaderTestSuite) TestWriter_WriteUUID() {
+	t := suite.T()
+
+	generated := fakes.FakeMessage()
+
+	provided := fakes.FakeMessage()
+	provided.UUID = uuid.New()
+
+	// GIVEN
+	before := time.Now().UTC().Add(-time.Minute)
+
+	_, err := suite.writeBatch([]types.Message{generated, provided})
+	require.NoError(t, err)
+
+	// WHEN
+	actual, err := suite.reader.Read(ctx, 10)
+
+	// THEN
+	require.NoError(t, err)
+	require.Len(t, actual, 2)
+
+	assert.Equal(t, uuid.Version(7), actual[0].UUID.Version())
+	assert.Equal(t, provided.UUID, actual[1].UUID)
+
+	for _, message := range actual {
+		assert.True(t, message.CreatedAt.After(before))
+		assert.True(t, message.PublishedAt.IsZero())
+	}
+
+	suite.markAll()
+}
+
+func (suite *WriterReaderTestSuite) TestWriter_WriteBinary() {
+	t := suite.T()
+
+	const binaryTable = "outbox_messages_binary"
+
+	binaryWriter, err := outbox.NewWriter(binaryTable, outbox.WithBinaryPayload())
+	require.NoError(t, err)
+
+	binaryReader, err := outbox.NewReader(binaryTable, suite.pool)
+	require.NoError(t, err)
+
+	fakeBinaryMessage := func() types.Message {
+		message := fakes.FakeMessage()
+		message.Payload = []byte(gofakeit.Sentence(5))
+		message.Payload[0] = 0xff // invalid UTF-8
+		message.ContentType = types.ContentTypeProtobuf
+		return message
+	}
+
+	in := []types.Message{
+		fakeBinaryMessage(), fakeBinaryMessage(), fakeBinaryMessage(), fakeBinaryMessage(), fakes.FakeMessage(),
+	}
+
+	// GIVEN JSON payload column
+	_, err = suite.writer.Write(ctx, suite.pool, in[0])
+
+	// THEN non-JSON content type is rejected
+	require.ErrorIs(t, err, outbox.ErrBinaryPayloadRequired)
+
+	// WHEN
+	_, err = binaryWriter.Write(ctx, suite.pool, in[0])
+	require.NoError(t, err)
+
+	_, err = binaryWriter.WriteBatch(ctx, suite.pool, in[1:3])
+	require.NoError(t, err)
+
+	_, err = binaryWriter.WriteBulk(ctx, suite.pool, in[3:])
+	require.NoError(t, err)
+
+	// THEN
+	actual, err := binaryReader.Read(ctx, 10)
+	require.NoError(t, err)
+	assertEqualMessages(t, in, actual)
+
+	_, err = binaryReader.Ack(ctx, types.Messages(actual).IDs())
+	require.NoError(t, err)
+}
+
+func (suite *WriterReaderTestSuite) TestWriter_WriteSchema() {
+	t := suite.T()
+
+	validator, err := jsonschema.New([]byte(`{"type": "object", "required": ["name"]}`))
+	require.NoError(t, err)
+
+	valid := fakes.FakeMessage()
+	valid.Payload = []byte(`{"name":"valid"}`)
+
+	invalid := valid
+	invalid.Payload = []byte(`{"content":"invalid"}`)
+
+	registry := &types.InMemorySchemaRegistry{}
+	registry.Register(valid.Topic, validator)
+
+	schemaWriter, err := outbox.NewWriter(outboxTable, outbox.WithSchemaRegistry(registry))
+	require.NoError(t, err)
+
+	// WHEN
+	_, err = schemaWriter.Write(ctx, suite.pool, invalid)
+	require.ErrorIs(t, err, types.ErrSchemaViolation)
+
+	_, err = schemaWriter.WriteBatch(ctx, suite.pool, []types.Message{valid, invalid})
+	require.ErrorIs(t, err, types.ErrSchemaViolation)
+
+	_, err = schemaWriter.Write(ctx, suite.pool, valid)
+	require.NoError(t, err)
+
+	// THEN only the valid message is written
+	actual, err := suite.reader.Read(ctx, 10)
+	require.NoError(t, err)
+	assertEqualMessages(t, []types.Message{valid}, actual)
+
+	suite.markAll()
+}
+
+func (suite *WriterReaderTestSuite) TestWriter_WriteCompressed() {
+	t := suite.T()
+
+	const binaryTable = "outbox_messages_binary"
+
+	compressingWriter, err := outbox.NewWriter(binaryTable,
+		outbox.WithBinaryPayload(), outbox.WithCompression(types.ContentEncodingGzip, 1024))
+	require.NoError(t, err)
+
+	binaryReader, err := outbox.NewReader(binaryTable, suite.pool)
+	require.NoError(t, err)
+
+	compressedReader, err := outbox.NewReader(binaryTable, suite.pool, outbox.WithReadCompressed())
+	require.NoError(t, err)
+
+	large := fakes.FakeMessage()
+	large.Payload = []byte(fmt.Sprintf(`{"content":%q}`, gofakeit.Paragraph(20, 10, 20, " ")))
+	require.Greater(t, len(large.Payload), 1024)
+
+	small := fakes.FakeMessage()
+
+	// GIVEN
+	_, err = compressingWriter.WriteBatch(ctx, suite.pool, []types.Message{large, small})
+	require.NoError(t, err)
+
+	// WHEN read with pass-through
+	compressed, err := compressedReader.Read(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, compressed, 2)
+
+	// THEN only the large payload is compressed
+	assert.Equal(t, types.ContentEncodingGzip, compressed[0].ContentEncoding())
+	assert.Less(t, len(compressed[0].Payload), len(large.Payload))
+	assert.Empty(t, compressed[1].ContentEncoding())
+	require.NoError(t, compressed[0].Validate())
+
+	// WHEN read by default
+	actual, err := binaryReader.Read(ctx, 10)
+	require.NoError(t, err)
+
+	// THEN payloads are decompressed
+	assertEqualMessages(t, []types.Message{large, small}, actual)
+
+	_, err = binaryReader.Ack(ctx, types.Messages(actual).IDs())
+	require.NoError(t, err)
+}
+
+func (suite *WriterReaderTestSuite) TestReader_ReadCorruptCompressed() {
+	t := suite.T()
+
+	const binaryTable = "outbox_messages_binary"
+
+	binaryWriter, err := outbox.NewWriter(binaryTable, outbox.WithBinaryPayload())
+	require.NoError(t, err)
+
+	binaryReader, err := outbox.NewReader(binaryTable, suite.pool)
+	require.NoError(t, err)
+
+	valid := fakes.FakeMessage()
+
+	// GIVEN a corrupt message inserted directly, as Writer rejects the reserved content_encoding metadata key
+	var corruptID int64
+	err = suite.pool.QueryRow(ctx,
+		"INSERT INTO "+binaryTable+" (broker, topic, metadata, payload) VALUES ($1, $2, $3, $4) RETURNING id",
+		"sns", "topic", `{"content_encoding":"gzip"}`, []byte("not gzip")).Scan(&corruptID)
+	require.NoError(t, err)
+
+	validID, err := binaryWriter.Write(ctx, suite.pool, valid)
+	require.NoError(t, err)
+
+	// WHEN
+	actual, err := binaryReader.Read(ctx, 10)
+
+	// THEN the corrupt message is reported, the valid one is read
+	var decodeErr *outbox.DecodeError
+	require.ErrorAs(t, err, &decodeErr)
+	assert.Contains(t, decodeErr.Errors, corruptID)
+	assertEqualMessages(t, []types.Message{valid}, actual)
+
+	_, err = binaryReader.Ack(ctx, []int64{corruptID, validID})
+	require.NoError(t, err)
+}
+
+func (suite *WriterReaderTestSuite) TestWriter_WriteReservedMetadata() {
+	for _, key := range []string{
+		types.MetadataContentEncoding, types.MetadataEncryptionKeyID, types.MetadataEncryptionDataKey,
+	} {
+		suite.Run(key, func() {
+			t := suite.T()
+
+			message := fakes.FakeMessage()
+			message.Metadata = map[string]string{key: "value"}
+
+			// WHEN
+			_, err := suite.writer.Write(ctx, suite.pool, message)
+
+			// THEN
+			require.ErrorIs(t, err, outbox.ErrMetadataKeyReserved)
+
+			_, err = suite.writer.WriteBatch(ctx, suite.pool, []types.Message{fakes.FakeMessage(), message})
+			require.ErrorIs(t, err, outbox.ErrMetadataKeyReserved)
+		})
+	}
+}
+
+func (suite *WriterReaderTestSuite) TestWriter_WriteEncrypted() {
+	t := suite.T()
+
+	const binaryTable = "outbox_messages_binary"
+
+	keys, err := encryption.NewStaticKeyProvider("k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	require.NoError(t, err)
+
+	envelope, err := encryption.NewEnvelope(keys)
+	require.NoError(t, err)
+
+	encryptingWriter, err := outbox.NewWriter(binaryTable,
+		outbox.WithBinaryPayload(), outbox.WithCompression(types.ContentEncodingGzip, 0), outbox.WithEncryption(envelope))
+	require.NoError(t, err)
+
+	binaryReader, err := outbox.NewReader(binaryTable, suite.pool)
+	require.NoError(t, err)
+
+	decryptingReader, err := outbox.NewReader(binaryTable, suite.pool, outbox.WithReadDecryptor(envelope))
+	require.NoError(t, err)
+
+	message := fakes.FakeMessage()
+
+	// GIVEN
+	_, err = encryptingWriter.Write(ctx, suite.pool, message)
+	require.NoError(t, err)
+
+	// WHEN read without decryptor
+	encrypted, err := binaryReader.Read(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, encrypted, 1)
+
+	// THEN the payload is encrypted
+	assert.True(t, encrypted[0].IsEncrypted())
+	assert.Equal(t, types.ContentEncodingGzip, encrypted[0].ContentEncoding())
+	assert.NotContains(t, string(encrypted[0].Payload), string(message.Payload))
+
+	// WHEN read with decryptor
+	actual, err := decryptingReader.Read(ctx, 10)
+	require.NoError(t, err)
+
+	// THEN the payload is decrypted and decompressed
+	assertEqualMessages(t, []types.Message{message}, actual)
+
+	_, err = binaryReader.Ack(ctx, types.Messages(actual).IDs())
+	require.NoError(t, err)
+}
+
+type fakeEntity struct {
+	Name  string `json:"name"`
+	Topic string `json:"-" outbox:"topic"`
+}
+
+func (suite *WriterReaderTestSuite) TestTypedWriter_Write() {
+	t := suite.T()
+
+	typedWriter, err := outbox.NewTypedWriter(suite.writer, types.JSONToMessage[fakeEntity]("sns", "default"))
+	require.NoError(t, err)
+
+	in := []fakeEntity{
+		{Name: gofakeit.Name(), Topic: "entities"},
+		{Name: gofakeit.Name()},
+		{Name: gofakeit.Name()},
+	}
+
+	// GIVEN
+	id, err := typedWriter.Write(ctx, suite.pool, in[0])
+	require.NoError(t, err)
+	assert.Positive(t, id)
+
+	ids, err := typedWriter.WriteBatch(ctx, suite.pool, in[1:])
+	require.NoError(t, err)
+	assert.Len(t, ids, 2)
+
+	// THEN
+	messages, err := suite.reader.Read(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+
+	decode := types.JSONFromMessage[fakeEntity]()
+	for idx, message := range messages {
+		wantTopic := "default"
+		if idx == 0 {
+			wantTopic = "entities"
+		}
+		assert.Equal(t, wantTopic, message.Topic)
+
+		actual, err := decode(message)
+		require.NoError(t, err)
+		assert.Equal(t, in[idx].Name, actual.Name)
+	}
+
+	suite.markAll()
+}
+
 func (suite *WriterReaderTestSuite) TestReader_ReadMessage() {
 	msg1 := fakes.FakeMessage()
 	msg2 := fakes.FakeMessage()
@@ -363,6 +1007,125 @@ func (suite *WriterReaderTestSuite) TestReader_ReadMessage() {
 	}
 }
 
+func (suite *WriterReaderTestSuite) TestReader_ReadDelayedMessage() {
+	// Postgres TIMESTAMP has microsecond precision
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	due := fakes.FakeMessage()
+	due.DeliverAt = now.Add(-time.Minute)
+
+	delayed := fakes.FakeMessage()
+	delayed.DeliverAt = now.Add(time.Hour)
+
+	immediate := fakes.FakeMessage()
+
+	tests := []struct {
+		name string
+		in   []types.Message
+		out  []types.Message
+	}{
+		{
+			name: "due message is read",
+			in:   types.Messages{due},
+			out:  types.Messages{due},
+		},
+		{
+			name: "delayed message is skipped",
+			in:   types.Messages{delayed},
+			out:  types.Messages{},
+		},
+		{
+			name: "delayed message does not block others",
+			in:   types.Messages{delayed, immediate, due},
+			out:  types.Messages{immediate, due},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			t := suite.T()
+
+			// GIVEN
+			for _, message := range tt.in {
+				_, err := suite.write(message)
+				require.NoError(t, err)
+			}
+
+			// WHEN
+			actual, err := suite.reader.Read(ctx, 10)
+
+			// THEN
+			require.NoError(t, err)
+			assertEqualMessages(t, tt.out, actual)
+
+			suite.markAll()
+			suite.markDelayed()
+		})
+	}
+}
+
+func (suite *WriterReaderTestSuite) TestReader_ReadPriority() {
+	low := make(types.Messages, 3)
+	for i := range low {
+		low[i] = fakes.FakeMessage()
+	}
+
+	high := make(types.Messages, 3)
+	for i := range high {
+		high[i] = fakes.FakeMessage()
+		high[i].Priority = 10
+	}
+
+	tests := []struct {
+		name    string
+		weights map[int16]int
+		limit   int
+		out     []types.Message
+	}{
+		{
+			name:  "priority is ignored by default",
+			limit: 3,
+			out:   types.Messages{low[0], low[1], low[2]},
+		},
+		{
+			name:    "higher priority first, lower priority is not starved",
+			weights: map[int16]int{10: 2},
+			limit:   3,
+			out:     types.Messages{high[0], high[1], low[0]},
+		},
+		{
+			name:    "all messages in weighted order",
+			weights: map[int16]int{10: 2, 0: 1},
+			limit:   6,
+			out:     types.Messages{high[0], high[1], low[0], high[2], low[1], low[2]},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			t := suite.T()
+
+			// GIVEN low priority messages are written before high priority ones
+			for _, message := range append(low, high...) {
+				_, err := suite.write(message)
+				require.NoError(t, err)
+			}
+
+			reader, err := outbox.NewReader(outboxTable, suite.pool, outbox.WithReadPriorityWeights(tt.weights))
+			require.NoError(t, err)
+
+			// WHEN
+			actual, err := reader.Read(ctx, tt.limit)
+
+			// THEN
+			require.NoError(t, err)
+			assertEqualMessages(t, tt.out, actual)
+
+			suite.markAll()
+		})
+	}
+}
+
 func (suite *WriterReaderTestSuite) TestWriter_AckMessage() {
 	msg1 := fakes.FakeMessage()
 	msg2 := fakes.FakeMessage()
@@ -462,8 +1225,13 @@ func assertEqualMessage(t *testing.T, expected, actual types.Message) {
 	t.Helper()
 
 	cmpOptions := cmp.Options{
+		// assigned on write
 		cmp.FilterPath(func(p cmp.Path) bool {
-			return p.Last().String() == ".ID"
+			switch p.Last().String() {
+			case ".ID", ".UUID", ".CreatedAt", ".PublishedAt":
+				return true
+			}
+			return false
 		}, cmp.Ignore()),
 		cmp.Comparer(func(x, y []byte) bool {
 			var xp, yp payload
@@ -578,7 +1346,7 @@ func (suite *WriterReaderTestSuite) writeStdLib(message types.Message) (_ int64,
 		}
 	}()
 
-	id, err := suite.writer.Write(ctx, tx, message)
+	id, err := suite.writer.Write(ctx, outbox.SQL(tx), message)
 	if err != nil {
 		return 0, fmt.Errorf("writer.Write: %w", err)
 	}
@@ -605,6 +1373,26 @@ func (suite *WriterReaderTestSuite) writeBatch(messages []types.Message) (_ []in
 	return ids, nil
 }
 
+// writeBatchIdempotent commits the transaction even if WriteBatch reports duplicates.
+func (suite *WriterReaderTestSuite) writeBatchIdempotent(messages []types.Message) ([]int64, error) {
+	tx, err := suite.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pool.Begin: %w", err)
+	}
+
+	ids, writeErr := suite.writer.WriteBatch(ctx, tx, messages)
+	if writeErr != nil && !errors.Is(writeErr, outbox.ErrMessageDuplicate) {
+		_ = tx.Rollback(ctx)
+		return nil, fmt.Errorf("writer.WriteBatch: %w", writeErr)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return ids, writeErr
+}
+
 func (suite *WriterReaderTestSuite) markAll() {
 	suite.T().Helper()
 
@@ -624,6 +1412,15 @@ func (suite *WriterReaderTestSuite) markAll() {
 	suite.Empty(actual)
 }
 
+// markDelayed marks messages which are not due yet as published, so they do not affect other tests.
+func (suite *WriterReaderTestSuite) markDelayed() {
+	suite.T().Helper()
+
+	_, err := suite.pool.Exec(ctx,
+		fmt.Sprintf("UPDATE %s SET published_at = NOW() WHERE published_at IS NULL AND deliver_at IS NOT NULL", outboxTable))
+	suite.noError(err)
+}
+
 func (suite *WriterReaderTestSuite) noError(err error) {
 	suite.T().Helper()
 	suite.Require().NoError(err)
@@ -639,6 +1436,12 @@ func maxInt(x, y int) int {
 
 // TestWriter_New is just to increase coverage.
 func (suite *WriterReaderTestSuite) TestWriter_New() {
+	keys, err := encryption.NewStaticKeyProvider("k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	suite.Require().NoError(err)
+
+	envelope, err := encryption.NewEnvelope(keys)
+	suite.Require().NoError(err)
+
 	tests := []struct {
 		name    string
 		table   string
@@ -659,6 +1462,38 @@ func (suite *WriterReaderTestSuite) TestWriter_New() {
 			table:   "outbox_messages",
 			options: []outbox.WriteOption{outbox.WithDisablePreparedBatch()},
 		},
+		{
+			name:    "compression without binary payload",
+			table:   "outbox_messages",
+			options: []outbox.WriteOption{outbox.WithCompression(types.ContentEncodingGzip, 0)},
+			wantErr: outbox.ErrBinaryPayloadRequired,
+		},
+		{
+			name:  "unsupported compression",
+			table: "outbox_messages",
+			options: []outbox.WriteOption{
+				outbox.WithBinaryPayload(), outbox.WithCompression("brotli", 0),
+			},
+			wantErr: types.ErrContentEncodingUnsupported,
+		},
+		{
+			name:  "with compression",
+			table: "outbox_messages",
+			options: []outbox.WriteOption{
+				outbox.WithBinaryPayload(), outbox.WithCompression(types.ContentEncodingZstd, 1024),
+			},
+		},
+		{
+			name:    "encryption without binary payload",
+			table:   "outbox_messages",
+			options: []outbox.WriteOption{outbox.WithEncryption(envelope)},
+			wantErr: outbox.ErrBinaryPayloadRequired,
+		},
+		{
+			name:    "with encryption",
+			table:   "outbox_messages",
+			options: []outbox.WriteOption{outbox.WithBinaryPayload(), outbox.WithEncryption(envelope)},
+		},
 	}
 
 	for _, tt := range tests {
@@ -694,12 +1529,12 @@ func (suite *WriterReaderTestSuite) TestWriter_WriteWithNilTx() {
 			wantErr: outbox.ErrTxNil,
 		},
 		{
-			name: "Write with unsupported tx type",
+			name: "Write with nil sql tx",
 			writeFn: func() error {
-				_, err := suite.writer.Write(ctx, struct{}{}, message)
+				_, err := suite.writer.Write(ctx, outbox.SQL(nil), message)
 				return err
 			},
-			wantErr: outbox.ErrTxUnsupportedType,
+			wantErr: outbox.ErrTxNil,
 		},
 		{
 			name: "WriteBatch with nil tx",
@@ -709,6 +1544,14 @@ func (suite *WriterReaderTestSuite) TestWriter_WriteWithNilTx() {
 			},
 			wantErr: outbox.ErrTxNil,
 		},
+		{
+			name: "WriteBatch with nil sql tx",
+			writeFn: func() error {
+				_, err := suite.writer.WriteBatch(ctx, outbox.SQL(nil), []types.Message{message})
+				return err
+			},
+			wantErr: outbox.ErrTxNil,
+		},
 	}
 
 	for _, tt := range tests {
@@ -721,6 +1564,47 @@ func (suite *WriterReaderTestSuite) TestWriter_WriteWithNilTx() {
 	}
 }
 
+// TestTypedWriter_New is just to increase coverage.
+func (suite *WriterReaderTestSuite) TestTypedWriter_New() {
+	tests := []struct {
+		name    string
+		writer  outbox.Writer
+		mapper  types.ToMessageFunc[fakeEntity]
+		wantErr error
+	}{
+		{
+			name:    "nil writer",
+			mapper:  types.JSONToMessage[fakeEntity]("sns", "topic"),
+			wantErr: outbox.ErrWriterNil,
+		},
+		{
+			name:    "nil mapper",
+			writer:  suite.writer,
+			wantErr: outbox.ErrMapperNil,
+		},
+		{
+			name:   "valid",
+			writer: suite.writer,
+			mapper: types.JSONToMessage[fakeEntity]("sns", "topic"),
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			t := suite.T()
+
+			typedWriter, err := outbox.NewTypedWriter(tt.writer, tt.mapper)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotNil(t, typedWriter)
+		})
+	}
+}
+
 // TestReader_New is just to increase coverage.
 func (suite *WriterReaderTestSuite) TestReader_New() {
 	tests := []struct {
@@ -743,10 +1627,20 @@ func (suite *WriterReaderTestSuite) TestReader_New() {
 			wantErr: outbox.ErrPoolNil,
 		},
 		{
-			name:    "with options",
+			name:    "invalid priority weight",
 			table:   "outbox_messages",
 			pool:    suite.pool,
-			options: []outbox.ReadOption{outbox.WithReadFilter(types.MessageFilter{Brokers: []string{"broker1"}})},
+			options: []outbox.ReadOption{outbox.WithReadPriorityWeights(map[int16]int{1: 0})},
+			wantErr: outbox.ErrPriorityWeightInvalid,
+		},
+		{
+			name:  "with options",
+			table: "outbox_messages",
+			pool:  suite.pool,
+			options: []outbox.ReadOption{
+				outbox.WithReadFilter(types.MessageFilter{Brokers: []string{"broker1"}}),
+				outbox.WithReadPriorityWeights(map[int16]int{1: 2}),
+			},
 		},
 	}
 